@@ -0,0 +1,89 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+// Region is a run of consecutive lines of the same kind, empty lines included
+type Region struct {
+	Kind FragmentKind
+	// Start is the index of the first line and End the index after the last line
+	Start int
+	End   int
+	Lines []*Line
+	// Span is the position of the region in the parsed text without the empty lines around it
+	Span Span
+}
+
+// Document is the email split in lines with the kind the parser gave every line,
+// use it to build your own heuristics on top of the parser
+type Document struct {
+	Lines   []*Line
+	Regions []*Region
+	// QuoteOnTop is true when the layout is LayoutBottomPosted
+	QuoteOnTop bool
+	IsForward  bool
+	Layout     Layout
+
+	kinds []FragmentKind
+}
+
+// Analyze returns the lines of the email with their flags, quote depth and regions
+func Analyze(plainMail string) *Document {
+	return defaultParser().Analyze(plainMail)
+}
+
+// Analyze returns the lines of the email with their flags, quote depth and regions
+func (p *Parser) Analyze(plainMail string) *Document {
+	lines, c, _ := p.classify(plainMail)
+	document := &Document{
+		Lines:      lines,
+		QuoteOnTop: c.layout == LayoutBottomPosted,
+		IsForward:  c.isForward,
+		Layout:     c.layout,
+		kinds:      make([]FragmentKind, len(lines)),
+	}
+
+	var current *Region
+	for i, line := range lines {
+		kind := c.kind(i)
+		document.kinds[i] = kind
+		if current == nil || current.Kind != kind {
+			current = &Region{Kind: kind, Start: i}
+			document.Regions = append(document.Regions, current)
+		}
+		current.Lines = append(current.Lines, line)
+		current.End = i + 1
+	}
+	for _, region := range document.Regions {
+		region.Span = spanOf(region.Lines)
+	}
+	return document
+}
+
+// Kind returns the kind of the line with the given index
+func (d *Document) Kind(lineIndex int) FragmentKind {
+	return d.kinds[lineIndex]
+}
+
+// LinesOf returns all lines of the given kinds
+func (d *Document) LinesOf(kinds ...FragmentKind) []*Line {
+	var lines []*Line
+	for i, line := range d.Lines {
+		if isOneOfKinds(d.kinds[i], kinds) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// ReplyLines returns the lines the sender actually wrote
+func (d *Document) ReplyLines() []*Line {
+	return d.LinesOf(FragmentReply)
+}
+
+// QuoteLines returns the quote headers and quoted lines of earlier messages
+func (d *Document) QuoteLines() []*Line {
+	return d.LinesOf(FragmentQuoteHeader, FragmentQuotedReply)
+}
+
+// SignatureLines returns the signature with its disclaimer and device footer
+func (d *Document) SignatureLines() []*Line {
+	return d.LinesOf(FragmentSignature, FragmentDisclaimer, FragmentDeviceFooter)
+}
@@ -0,0 +1,68 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+)
+
+func TestAnalyzeKaren(t *testing.T) {
+	document := Analyze(karenMail)
+	if document.QuoteOnTop || document.IsForward {
+		t.Errorf("expected quote on bottom without forward")
+	}
+
+	reply := removeWhiteSpaceBeforeAndAfter(joinLines(document.ReplyLines()))
+	if reply != Parse(karenMail) {
+		t.Errorf("expected: `%v` but is `%v`", Parse(karenMail), reply)
+	}
+	signature := document.SignatureLines()
+	if len(signature) == 0 || signature[0].ContentStripped != "Karen The Green" {
+		t.Errorf("expected the signature to start with `%v` but is `%v`", "Karen The Green", signature)
+	}
+	if !signature[0].PossibleSignatureLine && !signature[1].PossibleSignatureLine {
+		t.Errorf("expected signature lines to be possible signature lines")
+	}
+
+	quote := document.QuoteLines()
+	if len(quote) == 0 || !quote[0].IsQuoted || quote[0].QuoteDepth != 1 {
+		t.Errorf("expected the quote to start with a quoted line but is `%v`", quote)
+	}
+
+	var kinds []FragmentKind
+	for _, region := range document.Regions {
+		kinds = append(kinds, region.Kind)
+		for i, line := range region.Lines {
+			if line.Index != region.Start+i || document.Kind(line.Index) != region.Kind {
+				t.Errorf("line %v does not belong to region %v-%v", line.Index, region.Start, region.End)
+			}
+		}
+	}
+	expected := []FragmentKind{FragmentReply, FragmentSignature, FragmentDisclaimer, FragmentQuotedReply}
+	if len(kinds) != len(expected) {
+		t.Fatalf("expected: `%v` but is `%v`", expected, kinds)
+	}
+	for i := range expected {
+		if kinds[i] != expected[i] {
+			t.Errorf("region %v: expected: `%v` but is `%v`", i, expected[i], kinds[i])
+		}
+	}
+	if document.Regions[len(document.Regions)-1].End != len(document.Lines) {
+		t.Errorf("expected the last region to end at `%v`", len(document.Lines))
+	}
+}
+
+func TestQuoteDepth(t *testing.T) {
+	tests := map[string]int{
+		"no quote":        0,
+		"> quote":         1,
+		">> nested":       2,
+		"> > spaced":      2,
+		">>> > mixed":     4,
+		" > indented":     1,
+		"a > in the text": 0,
+	}
+	for line, expected := range tests {
+		if _, depth := quotePrefix(line, defaultQuotePrefixes); depth != expected {
+			t.Errorf("%v: expected: `%v` but is `%v`", line, expected, depth)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attribution is the information in a quote header like On DATE, NAME <EMAIL> wrote:
+type Attribution struct {
+	// Name is the display name of the author of the quoted message
+	Name string
+	// Address is nil when the header does not contain an email address
+	Address *mail.Address
+	// Raw is the header as it was found in the email
+	Raw string
+	// Date is zero when the header does not contain a complete date
+	Date time.Time
+}
+
+// ParseAttribution returns the author and date of a quote header like
+// On Monday, November 4, 2013 4:29 PM, John Smith <john.smith@example.org> wrote:
+// it returns nil when the header is not recognised as a quote header
+func ParseAttribution(header string) *Attribution {
+	return defaultParser().ParseAttribution(header)
+}
+
+// ParseAttribution returns the author and date of a quote header
+// or nil when the header is not recognised as a quote header
+func (p *Parser) ParseAttribution(header string) *Attribution {
+	oneLine := strings.Join(strings.Fields(normalizeLine(header)), space)
+	if !p.isQuotedEmailStart(foldCase(removeMarkdown(oneLine))) {
+		return nil
+	}
+	return p.parseAttribution(header)
+}
+
+//nolint:gochecknoglobals
+var attributionEmailRegex = regexp.MustCompile(`[^\s<>"'(),;:\[\]]+@[^\s<>"'(),;:\[\]]+\.[^\s<>"'(),;:\[\]]+`)
+
+func (p *Parser) parseAttribution(header string) *Attribution {
+	raw := removeWhiteSpaceBeforeAndAfter(header)
+	oneLine := removeMarkdown(strings.Join(strings.Fields(normalizeLine(raw)), space))
+
+	attribution := &Attribution{Raw: raw}
+
+	// the email address is replaced by a separator so the name before it is
+	// not glued to the words after it
+	words := strings.Fields(oneLine)
+	if location := attributionEmailRegex.FindStringIndex(oneLine); location != nil {
+		start := strings.Index(oneLine[:location[0]], "<")
+		if start == -1 {
+			start = location[0]
+		}
+		end := location[1]
+		for end < len(oneLine) && oneLine[end] == '>' {
+			end++
+		}
+		words = append(strings.Fields(oneLine[:start]), addressSeparator)
+		words = append(words, strings.Fields(oneLine[end:])...)
+
+		attribution.Address = &mail.Address{Address: oneLine[location[0]:location[1]]}
+	}
+
+	// Lotus Notes names like John Smith/ACME@ACME
+	for i, word := range words {
+		if slash := strings.Index(word, "/"); slash > 0 && strings.Contains(word[slash:], "@") {
+			words[i] = word[:slash]
+		}
+	}
+
+	tokens := p.classifyAttributionWords(words)
+	p.markOnAndWrote(tokens)
+	dateStart, dateEnd := bestDateRun(tokens)
+	if dateStart != -1 {
+		attribution.Date = dateFromTokens(tokens[dateStart:dateEnd])
+	}
+	attribution.Name = nameFromTokens(tokens, dateStart, dateEnd)
+	if attribution.Address != nil {
+		attribution.Address.Name = attribution.Name
+	}
+	return attribution
+}
+
+const addressSeparator = "\x00"
+
+type attributionTokenKind int
+
+const (
+	tokenWord attributionTokenKind = iota
+	tokenOn
+	tokenWrote
+	tokenSeparator
+	tokenConnector
+	tokenWeekday
+	tokenMonth
+	tokenNumber
+	tokenYear
+	tokenNumericDate
+	tokenTime
+	tokenMeridiem
+	tokenZone
+)
+
+type attributionToken struct {
+	word  string
+	lower string
+	kind  attributionTokenKind
+	// value is the month, day, year or hour depending on the kind
+	value int
+}
+
+func (p *Parser) classifyAttributionWords(words []string) []*attributionToken {
+	tokens := make([]*attributionToken, len(words))
+	for i, word := range words {
+		lower := foldCase(trimAttributionWord(word))
+		tokens[i] = &attributionToken{word: word, lower: lower}
+		if word == addressSeparator {
+			tokens[i].kind = tokenSeparator
+			continue
+		}
+		tokens[i].kind, tokens[i].value = p.classifyDateWord(lower)
+	}
+
+	// Vietnamese tháng 11 or th 2
+	for i := 0; i+1 < len(tokens); i++ {
+		next := tokens[i+1]
+		if next.kind != tokenNumber {
+			continue
+		}
+		if isOneOf(tokens[i].lower, p.language.MonthMarkers) && next.value >= 1 && next.value <= 12 {
+			tokens[i].kind, tokens[i].value = tokenMonth, next.value
+			next.kind = tokenConnector
+		} else if isOneOf(tokens[i].lower, p.language.WeekdayMarkers) && next.value <= 8 {
+			tokens[i].kind = tokenWeekday
+			next.kind = tokenConnector
+		}
+	}
+	return tokens
+}
+
+func (p *Parser) markOnAndWrote(tokens []*attributionToken) {
+	p.markWrotePhrases(tokens)
+
+	// Quoting John Smith <john@smith.org>:
+	for _, phrase := range p.language.Quoting {
+		phraseWords := strings.Fields(foldCase(phrase))
+		if matchesPhrase(tokens, 0, phraseWords) {
+			for j := range phraseWords {
+				tokens[j].kind = tokenOn
+			}
+		}
+	}
+
+	// on can only be the first word or the first word after wrote e.g. schrieb am
+	for i, token := range tokens {
+		isStart := i == 0 || tokens[i-1].kind == tokenWrote
+		if isStart && isOneOfPhraseStart(tokens, i, p.language.On) {
+			token.kind = tokenOn
+		}
+	}
+}
+
+func trimAttributionWord(word string) string {
+	word = strings.Trim(word, `,;()[]"'`)
+	return strings.TrimRight(word, ":.")
+}
+
+func (p *Parser) markWrotePhrases(tokens []*attributionToken) {
+	for i := range tokens {
+		for _, phrase := range p.language.Wrote {
+			phraseWords := strings.Fields(foldCase(phrase))
+			if !matchesPhrase(tokens, i, phraseWords) {
+				continue
+			}
+			for j := range phraseWords {
+				tokens[i+j].kind = tokenWrote
+			}
+		}
+	}
+}
+
+func isOneOfPhraseStart(tokens []*attributionToken, i int, phrases []string) bool {
+	for _, phrase := range phrases {
+		if matchesPhrase(tokens, i, strings.Fields(foldCase(phrase))) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesPhrase(tokens []*attributionToken, i int, phraseWords []string) bool {
+	if i+len(phraseWords) > len(tokens) {
+		return false
+	}
+	for j, phraseWord := range phraseWords {
+		if tokens[i+j].lower != phraseWord {
+			return false
+		}
+	}
+	return len(phraseWords) > 0
+}
+
+func isOneOf(v string, a []string) bool {
+	for _, c := range a {
+		if foldCase(c) == v {
+			return true
+		}
+	}
+	return false
+}
+
+//nolint:gochecknoglobals
+var (
+	dayNumberRegex   = regexp.MustCompile(`^([0-9]{1,4})(st|nd|rd|th|er|e)?$`)
+	numericDateRegex = regexp.MustCompile(`^([0-9]{1,4})([/.-])([0-9]{1,2})[/.-]([0-9]{1,4})$`)
+	timeRegex        = regexp.MustCompile(`^([0-9]{1,2})[:h]([0-9]{2})(?::([0-9]{2}))?(am|pm)?$`)
+	zoneRegex        = regexp.MustCompile(`^(?:gmt|utc)?([+-])([0-9]{1,2}):?([0-9]{2})?$`)
+)
+
+// zoneOffsets are the abbreviations mail clients add after the time, in hours from UTC
+//
+//nolint:gochecknoglobals
+var zoneOffsets = map[string]int{
+	"utc": 0, "gmt": 0, "z": 0,
+	"bst": 1, "cet": 1, "cest": 2,
+	"est": -5, "edt": -4, "cst": -6, "cdt": -5,
+	"mst": -7, "mdt": -6, "pst": -8, "pdt": -7,
+}
+
+func (p *Parser) classifyDateWord(lower string) (attributionTokenKind, int) {
+	if month, ok := p.language.Months[lower]; ok {
+		return tokenMonth, int(month)
+	}
+	if weekday, ok := p.language.Weekdays[lower]; ok {
+		return tokenWeekday, int(weekday)
+	}
+	if isOneOf(lower, p.language.DateConnectors) {
+		return tokenConnector, 0
+	}
+	if lower == "am" || lower == "pm" || lower == "a.m" || lower == "p.m" {
+		return tokenMeridiem, 0
+	}
+	if _, ok := zoneOffsets[lower]; ok || zoneRegex.MatchString(lower) {
+		return tokenZone, 0
+	}
+	if matches := dayNumberRegex.FindStringSubmatch(lower); matches != nil {
+		number, _ := strconv.Atoi(matches[1])
+		if len(matches[1]) == 4 {
+			return tokenYear, number
+		}
+		return tokenNumber, number
+	}
+	if numericDateRegex.MatchString(lower) {
+		return tokenNumericDate, 0
+	}
+	if timeRegex.MatchString(lower) {
+		return tokenTime, 0
+	}
+	return tokenWord, 0
+}
+
+func isStrongDateToken(kind attributionTokenKind) bool {
+	switch kind {
+	case tokenWeekday, tokenMonth, tokenNumber, tokenYear, tokenNumericDate, tokenTime:
+		return true
+	}
+	return false
+}
+
+//nolint:gochecknoglobals
+var dateTokenScores = map[attributionTokenKind]int{
+	tokenWeekday:     1,
+	tokenNumber:      1,
+	tokenMonth:       2,
+	tokenTime:        2,
+	tokenYear:        3,
+	tokenNumericDate: 4,
+}
+
+// bestDateRun returns the start and end of the words which are most likely the date
+// e.g. Monday, November 4, 2013 4:29 PM
+func bestDateRun(tokens []*attributionToken) (int, int) {
+	bestStart, bestEnd, bestScore := -1, -1, 0
+	for start := 0; start < len(tokens); start++ {
+		if !isStrongDateToken(tokens[start].kind) {
+			continue
+		}
+		end, score := dateRunEnd(tokens, start)
+		if score > bestScore {
+			bestStart, bestEnd, bestScore = start, end, score
+		}
+		start = end - 1
+	}
+	return bestStart, bestEnd
+}
+
+func dateRunEnd(tokens []*attributionToken, start int) (int, int) {
+	var score int
+	var hasYear, hasTime bool
+	end := start
+	for i := start; i < len(tokens); i++ {
+		kind := tokens[i].kind
+		isComplete := hasYear && hasTime
+		switch {
+		case kind == tokenMeridiem && i > start && tokens[i-1].kind == tokenTime:
+		case kind == tokenZone && hasTime:
+		case isComplete:
+			return end, score
+		case kind == tokenConnector:
+			continue
+		case isStrongDateToken(kind):
+			score += dateTokenScores[kind]
+			hasYear = hasYear || kind == tokenYear || kind == tokenNumericDate
+			hasTime = hasTime || kind == tokenTime
+		default:
+			return end, score
+		}
+		end = i + 1
+	}
+	return end, score
+}
+
+func dateFromTokens(tokens []*attributionToken) time.Time {
+	var year, day, hour, minute, second int
+	var month time.Month
+	location := time.UTC
+	for _, token := range tokens {
+		switch token.kind {
+		case tokenYear:
+			year = token.value
+		case tokenMonth:
+			if month == 0 {
+				month = time.Month(token.value)
+			}
+		case tokenNumber:
+			if day == 0 && token.value >= 1 && token.value <= 31 {
+				day = token.value
+			}
+		case tokenNumericDate:
+			year, month, day = parseNumericDate(token.lower)
+		case tokenTime:
+			hour, minute, second = parseTime(token.lower)
+		case tokenMeridiem:
+			hour = applyMeridiem(hour, strings.HasPrefix(token.lower, "p"))
+		case tokenZone:
+			location = parseZone(token.lower)
+		}
+	}
+
+	if year < 100 && year > 0 {
+		year += 2000
+	}
+	if year == 0 || month == 0 || day == 0 {
+		return time.Time{}
+	}
+	// time.Date moves values which are out of range to the next month or day,
+	// no date is better than a wrong one e.g. 13/13/2013 or 31 February
+	if month > time.December || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}
+	}
+	date := time.Date(year, month, day, hour, minute, second, 0, location)
+	if date.Day() != day {
+		return time.Time{}
+	}
+	return date
+}
+
+func parseNumericDate(v string) (int, time.Month, int) {
+	matches := numericDateRegex.FindStringSubmatch(v)
+	a, _ := strconv.Atoi(matches[1])
+	separator := matches[2]
+	b, _ := strconv.Atoi(matches[3])
+	c, _ := strconv.Atoi(matches[4])
+
+	// 2013/11/1 or 2011-03-01
+	if len(matches[1]) == 4 {
+		return a, time.Month(b), c
+	}
+	// 04/11/2013 is the 4th of November everywhere except in the US
+	monthFirst := separator == "/"
+	if a > 12 {
+		monthFirst = false
+	} else if b > 12 {
+		monthFirst = true
+	}
+	if monthFirst {
+		return c, time.Month(a), b
+	}
+	return c, time.Month(b), a
+}
+
+func parseTime(v string) (int, int, int) {
+	matches := timeRegex.FindStringSubmatch(v)
+	hour, _ := strconv.Atoi(matches[1])
+	minute, _ := strconv.Atoi(matches[2])
+	second, _ := strconv.Atoi(matches[3])
+	if matches[4] != "" {
+		hour = applyMeridiem(hour, matches[4] == "pm")
+	}
+	return hour, minute, second
+}
+
+func applyMeridiem(hour int, pm bool) int {
+	if pm && hour < 12 {
+		return hour + 12
+	}
+	if !pm && hour == 12 {
+		return 0
+	}
+	return hour
+}
+
+func parseZone(v string) *time.Location {
+	if offset, ok := zoneOffsets[v]; ok {
+		return time.FixedZone(strings.ToUpper(v), offset*60*60)
+	}
+	matches := zoneRegex.FindStringSubmatch(v)
+	hours, _ := strconv.Atoi(matches[2])
+	minutes, _ := strconv.Atoi(matches[3])
+	seconds := hours*60*60 + minutes*60
+	if matches[1] == "-" {
+		seconds = -seconds
+	}
+	return time.FixedZone("", seconds)
+}
+
+// nameFromTokens returns the words closest before the email address which are not part of the date
+func nameFromTokens(tokens []*attributionToken, dateStart int, dateEnd int) string {
+	isNameWord := func(i int) bool {
+		if i >= dateStart && i < dateEnd {
+			return false
+		}
+		switch tokens[i].kind {
+		case tokenOn, tokenWrote, tokenSeparator:
+			return false
+		}
+		return true
+	}
+
+	end := len(tokens)
+	for i, token := range tokens {
+		if token.kind == tokenSeparator {
+			end = i
+			break
+		}
+	}
+
+	var runStart, runEnd int
+	for i := 0; i < end; i++ {
+		if !isNameWord(i) {
+			continue
+		}
+		start := i
+		for i < end && isNameWord(i) {
+			i++
+		}
+		runStart, runEnd = start, i
+	}
+
+	var words []string
+	for _, token := range tokens[runStart:runEnd] {
+		words = append(words, token.word)
+	}
+	return strings.Trim(strings.Join(words, space), `"',:;<> `)
+}
+
+// parseDate reads a date like Thu, Oct 24, 2013 at 3:57 PM in any of the supported languages
+func (p *Parser) parseDate(v string) time.Time {
+	if date, err := mail.ParseDate(v); err == nil {
+		return date
+	}
+	tokens := p.classifyAttributionWords(strings.Fields(v))
+	start, end := bestDateRun(tokens)
+	if start == -1 {
+		return time.Time{}
+	}
+	return dateFromTokens(tokens[start:end])
+}
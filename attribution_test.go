@@ -0,0 +1,161 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAttribution(t *testing.T) {
+	tests := []struct {
+		header  string
+		name    string
+		address string
+		date    time.Time
+	}{
+		{
+			header:  "On Monday, November 4, 2013 4:29 PM, John Smith <john.smith@example.org> wrote:",
+			name:    "John Smith",
+			address: "john.smith@example.org",
+			date:    time.Date(2013, time.November, 4, 16, 29, 0, 0, time.UTC),
+		},
+		{
+			header:  "Op za 8 mei 2021 om 12:09 schreef\nRichard Lindhout <richardlindhout96@gmail.com>:",
+			name:    "Richard Lindhout",
+			address: "richardlindhout96@gmail.com",
+			date:    time.Date(2021, time.May, 8, 12, 9, 0, 0, time.UTC),
+		},
+		{
+			header: "On Oct 1, 2012, at 11:55 PM, Dave Tapley wrote:",
+			name:   "Dave Tapley",
+			date:   time.Date(2012, time.October, 1, 23, 55, 0, 0, time.UTC),
+		},
+		{
+			header:  "2013/11/1 John Smith <\njohn@smith.org>",
+			name:    "John Smith",
+			address: "john@smith.org",
+			date:    time.Date(2013, time.November, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			header: "On Tue, 2011-03-01 at 18:02 +0530, Abhishek Kona wrote:",
+			name:   "Abhishek Kona",
+			date:   time.Date(2011, time.March, 1, 18, 2, 0, 0, time.FixedZone("", 5*60*60+30*60)),
+		},
+		{
+			header:  "On Fri, Feb 24, 2012 at 10:19 AM, <examples@email.goalengine.com> wrote:",
+			address: "examples@email.goalengine.com",
+			date:    time.Date(2012, time.February, 24, 10, 19, 0, 0, time.UTC),
+		},
+		{
+			header:  "Le lun. 4 nov. 2013 à 16:29, Jan de Smit <jan@example.org> a écrit :",
+			name:    "Jan de Smit",
+			address: "jan@example.org",
+			date:    time.Date(2013, time.November, 4, 16, 29, 0, 0, time.UTC),
+		},
+		{
+			header:  "Am Mo., 4. Nov. 2013 um 16:29 Uhr schrieb Jan Müller <jan@example.org>:",
+			name:    "Jan Müller",
+			address: "jan@example.org",
+			date:    time.Date(2013, time.November, 4, 16, 29, 0, 0, time.UTC),
+		},
+		{
+			header: "John Smith/ACME@ACME wrote on 11/04/2013 04:29:12 PM:",
+			name:   "John Smith",
+			date:   time.Date(2013, time.November, 4, 16, 29, 12, 0, time.UTC),
+		},
+		{
+			header: "John Smith wrote on 04-11-2013 16:29:",
+			name:   "John Smith",
+			date:   time.Date(2013, time.November, 4, 16, 29, 0, 0, time.UTC),
+		},
+		{
+			header:  "Quoting John Smith <john@smith.org>:",
+			name:    "John Smith",
+			address: "john@smith.org",
+		},
+		{
+			header: "On Mon, Nov 04, 2013 at 04:29:00PM +0100, John Smith wrote:",
+			name:   "John Smith",
+			date:   time.Date(2013, time.November, 4, 16, 29, 0, 0, time.FixedZone("", 60*60)),
+		},
+		{
+			header:  "Em seg., 4 de nov. de 2013 às 16:29, João Silva <joao@example.org> escreveu:",
+			name:    "João Silva",
+			address: "joao@example.org",
+			date:    time.Date(2013, time.November, 4, 16, 29, 0, 0, time.UTC),
+		},
+		{
+			header:  "Den mån 4 nov. 2013 kl 16:29 skrev Sven Svensson <sven@example.org>:",
+			name:    "Sven Svensson",
+			address: "sven@example.org",
+			date:    time.Date(2013, time.November, 4, 16, 29, 0, 0, time.UTC),
+		},
+		{
+			header:  "W dniu pon., 4.11.2013 o 16:29 Jan Kowalski <jan@example.org> napisał:",
+			name:    "Jan Kowalski",
+			address: "jan@example.org",
+			date:    time.Date(2013, time.November, 4, 16, 29, 0, 0, time.UTC),
+		},
+		{
+			header:  "Vào Th 2, 4 thg 11, 2013 vào lúc 16:29 Nguyen Van A <a@example.org> đã viết:",
+			name:    "Nguyen Van A",
+			address: "a@example.org",
+			date:    time.Date(2013, time.November, 4, 16, 29, 0, 0, time.UTC),
+		},
+	}
+	for _, test := range tests {
+		attribution := ParseAttribution(test.header)
+		if attribution == nil {
+			t.Errorf("Should be an attribution: %v", test.header)
+			continue
+		}
+		if attribution.Name != test.name {
+			t.Errorf("expected name: `%v` but is `%v`", test.name, attribution.Name)
+		}
+		var address string
+		if attribution.Address != nil {
+			address = attribution.Address.Address
+		}
+		if address != test.address {
+			t.Errorf("expected address: `%v` but is `%v`", test.address, address)
+		}
+		if !attribution.Date.Equal(test.date) {
+			t.Errorf("expected date: `%v` but is `%v`", test.date, attribution.Date)
+		}
+	}
+
+	if ParseAttribution("You see this this the problem") != nil {
+		t.Errorf("Should not be an attribution")
+	}
+}
+
+func TestFragmentAttribution(t *testing.T) {
+	email := ParseEmail(richardReverseMail)
+	attribution := email.Fragments[0].Attribution
+	if attribution == nil {
+		t.Fatalf("quote header should have an attribution")
+	}
+	if attribution.Name != "The Hiring Engine" {
+		t.Errorf("expected: `%v` but is `%v`", "The Hiring Engine", attribution.Name)
+	}
+}
+
+func TestParseAttributionDateOutOfRange(t *testing.T) {
+	headers := []string{
+		"On 13/13/2013, John Smith <john@smith.org> wrote:",
+		"On 2013/11/1 at 25:10, John Smith <john@smith.org> wrote:",
+		"On Nov 4, 2013 at 4:61 PM, John Smith <john@smith.org> wrote:",
+	}
+	for _, header := range headers {
+		attribution := ParseAttribution(header)
+		if attribution == nil {
+			t.Errorf("Should be an attribution: %v", header)
+			continue
+		}
+		if !attribution.Date.IsZero() {
+			t.Errorf("%v: expected no date but is `%v`", header, attribution.Date)
+		}
+		if attribution.Name != "John Smith" {
+			t.Errorf("expected name: `%v` but is `%v`", "John Smith", attribution.Name)
+		}
+	}
+}
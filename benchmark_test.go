@@ -0,0 +1,98 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// longThread returns a reply above a quoted thread with the given amount of quoted messages
+func longThread(messages int) string {
+	var b strings.Builder
+	b.WriteString("Thanks, I will look at it tomorrow.\n\nBest regards,\nKaren Green\nGraphic Designer\nTel: +44423423423423\n\n")
+	for i := 0; i < messages; i++ {
+		depth := strings.Repeat("> ", i%5)
+		fmt.Fprintf(&b, "%vOn Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\n", depth)
+		fmt.Fprintf(&b, "%v> Message %v of the thread with some text about the deploy.\n", depth, i)
+		fmt.Fprintf(&b, "%v> Did you check the logs?\n", depth)
+		fmt.Fprintf(&b, "%v>\n", depth)
+		fmt.Fprintf(&b, "%v> John Smith\n", depth)
+		fmt.Fprintf(&b, "%v> Tel: +31622222222\n\n", depth)
+	}
+	return b.String()
+}
+
+// longReply returns a reply with the given amount of paragraphs and a signature without quoted text
+func longReply(paragraphs int) string {
+	var b strings.Builder
+	for i := 0; i < paragraphs; i++ {
+		fmt.Fprintf(&b, "Paragraph %v of a long reply about the deploy of the new version.\n", i)
+		b.WriteString("Tel: +31622222222\nwww.example.org\n\n")
+	}
+	b.WriteString("Best regards,\nKaren Green\n")
+	return b.String()
+}
+
+func BenchmarkParseThread(b *testing.B) {
+	for _, messages := range []int{10, 100, 1000} {
+		mail := longThread(messages)
+		b.Run(fmt.Sprintf("%v_lines", strings.Count(mail, enter)), func(b *testing.B) {
+			b.SetBytes(int64(len(mail)))
+			for i := 0; i < b.N; i++ {
+				Parse(mail)
+			}
+		})
+	}
+}
+
+func BenchmarkParseLongReply(b *testing.B) {
+	for _, paragraphs := range []int{10, 100, 1000} {
+		mail := longReply(paragraphs)
+		b.Run(fmt.Sprintf("%v_lines", strings.Count(mail, enter)), func(b *testing.B) {
+			b.SetBytes(int64(len(mail)))
+			for i := 0; i < b.N; i++ {
+				Parse(mail)
+			}
+		})
+	}
+}
+
+func BenchmarkContainsWebsite(b *testing.B) {
+	p := defaultParser()
+	for i := 0; i < b.N; i++ {
+		p.containsWebsite("*WEB     webRidge.nl <https://webridge.nl/>*")
+	}
+}
+
+func BenchmarkParseQuotedBlock(b *testing.B) {
+	for _, quoted := range []int{100, 1000, 10000} {
+		mail := "Hi\n" + strings.Repeat("> a\n", quoted) + "x"
+		b.Run(fmt.Sprintf("%v_lines", quoted+2), func(b *testing.B) {
+			b.SetBytes(int64(len(mail)))
+			for i := 0; i < b.N; i++ {
+				Parse(mail)
+			}
+		})
+	}
+}
+
+func BenchmarkParseAdversarial(b *testing.B) {
+	mails := map[string]func(n int) string{
+		"on_words": func(n int) string { return strings.Repeat("x On ", n) + ":" },
+		"headers":  func(n int) string { return "Hi\n\n" + strings.Repeat("To: a@b.com\n", n) },
+		"indented": func(n int) string {
+			return "Hi\n\nOn Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\n" + strings.Repeat("\tquoted\n", n)
+		},
+	}
+	for name, mail := range mails {
+		for _, n := range []int{1000, 10000} {
+			content := mail(n)
+			b.Run(fmt.Sprintf("%v_%v", name, n), func(b *testing.B) {
+				b.SetBytes(int64(len(content)))
+				for i := 0; i < b.N; i++ {
+					Parse(content)
+				}
+			})
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"unicode"
 )
 
+// Line is a line of the email with the information the parser uses to classify it
 type Line struct {
 	Index                 int
 	Content               string
@@ -13,6 +14,16 @@ type Line struct {
 	IsQuoted              bool
 	IsEmpty               bool
 	PossibleSignatureLine bool
+	// QuoteDepth is the amount of quote characters before the content e.g. 2 for > > text
+	QuoteDepth int
+	// QuotePrefix is the quote characters with the whitespace around them as written e.g. "> > "
+	QuotePrefix string
+	// Offset is the byte offset of the line in the parsed text and Number its line number starting at 1,
+	// a quote header which started in the middle of a line has the number of that line
+	Offset int
+	Number int
+
+	features *features
 }
 
 const (
@@ -23,79 +34,204 @@ const (
 var dot = "."
 
 func Parse(plainMail string) string {
-	lines := plainMailToLines(plainMail)
+	return defaultParser().Parse(plainMail)
+}
+
+// ParseEmail splits the email in fragments like the reply, signature and quoted reply
+func ParseEmail(plainMail string) *Email {
+	return defaultParser().ParseEmail(plainMail)
+}
+
+// Parse returns the reply without signature and quoted reply
+func (p *Parser) Parse(plainMail string) string {
+	return p.ParseEmail(plainMail).Reply()
+}
+
+// ParseEmail splits the email in fragments like the reply, signature and quoted reply
+func (p *Parser) ParseEmail(plainMail string) *Email {
+	return p.emailFromClassification(p.classify(plainMail))
+}
+
+// emailFromClassification groups the classified lines in fragments
+func (p *Parser) emailFromClassification(lines []*Line, c *classification, forward *Forward) *Email {
+	email := p.newEmail(lines, c)
+	if forward != nil {
+		forward.Comment = email.Reply()
+		email.Forward = forward
+	}
+	return email
+}
+
+// classify decides the kind of every line of the email
+func (p *Parser) classify(plainMail string) ([]*Line, *classification, *Forward) {
+	return p.classifyLines(p.plainMailToLines(plainMail))
+}
+
+// classifyLines decides the kind of every line
+func (p *Parser) classifyLines(lines []*Line) ([]*Line, *classification, *Forward) {
+	c := newClassification(lines)
 
-	var plainLines []string
-	if isQuoteOnTop(plainMail) {
-		plainLines = getPlainLinesWithQuotedReplyOnTop(lines)
+	// the forwarded message is not part of the reply so only the comment above it is parsed
+	var forward *Forward
+	if forwardStart := p.detectForwardStart(lines); forwardStart != -1 {
+		c.isForward = true
+		forward = p.classifyForward(forwardStart, lines, c)
+		p.classifyLinesWithQuotedReplyOnBottom(lines[:forwardStart], c)
 	} else {
-		plainLines = getPlainLinesWithQuotedReplyOnBottom(lines)
+		c.layout = p.detectLayout(lines)
+		switch {
+		case c.layout == LayoutInterleaved:
+			p.classifyInterleaved(lines, c)
+		case c.layout == LayoutBottomPosted && p.startsWithQuote(lines):
+			p.classifyLinesWithQuotedReplyOnTop(lines, c)
+		case c.layout == LayoutBottomPosted:
+			// the text above the quote is an introduction like Hi John,
+			p.classifyInterleaved(lines, c)
+		default:
+			p.classifyLinesWithQuotedReplyOnBottom(lines, c)
+		}
 	}
+	return lines, c, forward
+}
 
-	return removeWhiteSpaceBeforeAndAfter(
-		strings.Join(plainLines, enter),
-	)
+func (p *Parser) plainMailToLines(plainMail string) []*Line {
+	return p.limitedLines(plainMail, 0, nil)
 }
 
-func plainMailToLines(plainMail string) []*Line {
-	baseLines := strings.Split(plainMail, enter)
+// limitedLines splits the email in lines and cuts lines which are longer than maxLineLength bytes
+// when it is not zero, it stops when done is closed
+func (p *Parser) limitedLines(plainMail string, maxLineLength int, done <-chan struct{}) []*Line {
+	sourceLines := p.splitMidLineQuoteHeaders(plainMail, maxLineLength, done)
 
 	// first save lines with some information we will use later on while parsing
-	lines := make([]*Line, len(baseLines))
-	for i, baseLine := range baseLines {
-		contentStripped := removeWhitespace(baseLine)
+	lines := make([]*Line, 0, len(sourceLines))
+	for i, sourceLine := range sourceLines {
+		if isDone(done) {
+			break
+		}
+		baseLine := sourceLine.content
+		contentStripped := removeWhitespace(normalizeLine(baseLine))
 		withoutMarkdown := removeMarkdown(contentStripped)
-		lines[i] = &Line{
+		prefix, depth := quotePrefix(baseLine, p.quotePrefixes)
+		lines = append(lines, &Line{
 			Index:                 i,
 			Content:               baseLine,
 			ContentStripped:       withoutMarkdown,
 			IsEmpty:               isWhitespace(contentStripped),
-			IsQuoted:              strings.HasPrefix(baseLine, ">"),
-			PossibleSignatureLine: isPossibleSignatureLine(withoutMarkdown),
-		}
+			IsQuoted:              depth > 0,
+			PossibleSignatureLine: p.isPossibleSignatureLine(withoutMarkdown),
+			QuoteDepth:            depth,
+			QuotePrefix:           prefix,
+			Offset:                sourceLine.offset,
+			Number:                sourceLine.number,
+		})
+	}
+	p.attachFeatures(lines, done)
+	if p.markIndentedQuotes(lines) {
+		// the features and quote starts depend on which lines are quoted
+		p.attachFeatures(lines, done)
 	}
 	return lines
 }
 
-func getPlainLinesWithQuotedReplyOnBottom(lines []*Line) []string {
-	//nolint:prealloc
-	var finalLines []string
+// attachFeatures computes the features of the lines and gives every line a pointer to them
+func (p *Parser) attachFeatures(lines []*Line, done <-chan struct{}) {
+	f := newFeatures(lines)
+	f.headers = p.newHeaderRuns(lines)
+	f.done = done
+	for _, line := range lines {
+		line.features = f
+	}
+}
+
+func (p *Parser) classifyLinesWithQuotedReplyOnBottom(lines []*Line, c *classification) {
 	for i, line := range lines {
-		if isSignatureStart(i, line, lines) {
+		if canceled(lines) {
+			break
+		}
+		// > lines till the end of the mail are a quoted reply without header
+		if isTrailingQuotedBlock(i, lines) {
+			for j := i; j < len(lines); j++ {
+				c.set(j, FragmentQuotedReply, RuleQuotedBlock)
+			}
+			break
+		}
+		if rule := p.signatureStartRule(i, line, lines, c); rule != "" {
+			end := p.classifySignature(i, lines, c, rule)
+			if end < len(lines) {
+				p.classifyQuote(end, lines, c)
+			}
 			break
 		}
-		multilineQuoteReply, _ := detectQuotedEmailStart(i, line, lines)
+		multilineQuoteReply, _ := p.detectQuotedEmailStart(i, line, lines)
 		if multilineQuoteReply {
+			p.classifyQuote(i, lines, c)
 			break
 		}
-		finalLines = append(finalLines, line.Content)
+		c.set(i, FragmentReply, RuleReply)
 	}
-	return finalLines
 }
 
-func getPlainLinesWithQuotedReplyOnTop(lines []*Line) []string {
-	//nolint:prealloc
-	var finalLines []string
+// minQuotedBlockLines is the minimum amount of > lines of a quoted reply without header,
+// a single > line is seen as a citation in the reply
+const minQuotedBlockLines = 2
+
+// isTrailingQuotedBlock tells if the line starts a run of > lines which is only followed by empty lines
+func isTrailingQuotedBlock(lineIndex int, lines []*Line) bool {
+	if !lines[lineIndex].IsQuoted {
+		return false
+	}
+	f := featuresOf(lines)
+	end := len(lines)
+	return f.unquotedFilled[end] == f.unquotedFilled[lineIndex] &&
+		f.quoted[end]-f.quoted[lineIndex] >= minQuotedBlockLines
+}
+
+func (p *Parser) classifyLinesWithQuotedReplyOnTop(lines []*Line, c *classification) {
 	var quotedStartSeen bool
 	var normalLineSeen bool
 	var skipNextLine bool
+	var skipHeaderLines int
 	for i, line := range lines {
-		multiLine, singleLine := detectQuotedEmailStart(i, line, lines)
+		if canceled(lines) {
+			break
+		}
+		multiLine, singleLine := p.detectQuotedEmailStart(i, line, lines)
 		// start of quoted text can be ignored
 		if multiLine {
+			c.set(i, FragmentQuoteHeader, RuleQuoteHeader)
 			quotedStartSeen = true
-			if !singleLine {
+			if length := p.blockQuoteHeaderLength(i, lines); length > 0 {
+				skipHeaderLines = length - 1
+			} else if !singleLine {
 				skipNextLine = true
 			}
 			continue
 		}
 
+		// the other lines of an Outlook or wrapped quote header
+		if skipHeaderLines > 0 {
+			skipHeaderLines--
+			c.set(i, FragmentQuoteHeader, RuleQuoteHeaderContinued)
+			continue
+		}
+
 		// skip this line because this is still the quote start
 		if skipNextLine {
 			skipNextLine = false
+			if p.quoteHeaderLength(i-1, lines) == 2 {
+				c.set(i, FragmentQuoteHeader, RuleQuoteHeaderContinued)
+			} else {
+				c.set(i, FragmentQuotedReply, RuleQuoted)
+			}
 			continue
 		}
 
+		// a > line on top starts the quoted text without a quote header
+		if line.IsQuoted {
+			quotedStartSeen = true
+		}
+
 		if quotedStartSeen &&
 			!line.IsQuoted &&
 			!line.IsEmpty {
@@ -103,26 +239,34 @@ func getPlainLinesWithQuotedReplyOnTop(lines []*Line) []string {
 		}
 
 		if normalLineSeen && quotedStartSeen {
-			if isSignatureStart(i, line, lines) {
+			if rule := p.signatureStartRule(i, line, lines, c); rule != "" {
+				end := p.classifySignature(i, lines, c, rule)
+				if end < len(lines) {
+					p.classifyQuote(end, lines, c)
+				}
 				break
 			}
-			finalLines = append(finalLines, line.Content)
+			c.set(i, FragmentReply, RuleReply)
+			continue
 		}
-
+		c.set(i, FragmentQuotedReply, RuleQuotedBeforeReply)
 	}
-	return finalLines
 }
 
-func isQuoteOnTop(plainMail string) bool {
-	plainMailStrippedWhiteSpace := removeWhitespace(plainMail)
-	lines := plainMailToLines(plainMailStrippedWhiteSpace)
+// isQuoteOnTop tells if the reply is written below the quoted text
+func (p *Parser) isQuoteOnTop(plainMail string) bool {
+	return p.detectLayout(p.plainMailToLines(plainMail)) == LayoutBottomPosted
+}
+
+// startsWithQuote tells if the first line which is not empty is a quote header or a > line
+func (p *Parser) startsWithQuote(lines []*Line) bool {
 	for i, line := range lines {
-		isQuoteStarted, _ := detectQuotedEmailStart(i, line, lines)
-		if i == 0 && isQuoteStarted {
-			return true
+		if line.IsEmpty {
+			continue
 		}
+		isQuoteStart, _ := p.detectQuotedEmailStart(i, line, lines)
+		return isQuoteStart || line.IsQuoted
 	}
-
 	return false
 }
 
@@ -139,23 +283,24 @@ func lineBeforeAndAfter(lineIndex int, lines []*Line) (*Line, *Line) {
 	return before, after
 }
 
-func isSignatureStart(lineIndex int, line *Line, lines []*Line) bool {
+// signatureStartRule returns the rule which detected the start of a signature or an empty rule
+func (p *Parser) signatureStartRule(lineIndex int, line *Line, lines []*Line, c *classification) Rule {
 	// first line is probably not the signature
 	if lineIndex == 0 {
-		return false
+		return ""
 	}
 
-	lowerLine := strings.ToLower(line.ContentStripped)
+	lowerLine := foldCase(line.ContentStripped)
 
 	// --
 	// my name
 	if isValidSignatureFormat(lowerLine) {
-		return true
+		return RuleSignatureSeparator
 	}
 
 	// e.g. with best regards,
-	if detectGreetings(strings.ToLower(line.ContentStripped)) {
-		return true
+	if p.detectGreetings(foldCase(line.ContentStripped)) {
+		return RuleGreeting
 	}
 
 	// smart system to detect signature + disclaimers like
@@ -170,26 +315,52 @@ func isSignatureStart(lineIndex int, line *Line, lines []*Line) bool {
 	//
 	// Lorem Ipsum is simply dummy text of the printing and typesetting industry. Lorem Ipsum has been the industry's standard dummy text ever since the 1500s, when an unknown printer took a galley of type and scrambled it to make a type specimen book. It has survived not only five centuries, but also the leap into electronic typesetting, remaining essentially unchanged.
 
-	if detectSignature(lineIndex, line, lines) {
-		return true
+	if percentMatched, possible := p.detectSignature(lineIndex, line, lines); possible {
+		c.setSignatureMatch(lineIndex, percentMatched)
+		if percentMatched > p.signatureMatchPercentage {
+			return RuleSignatureLines
+		}
 	}
 
 	// Sent from .... iphone/blackberry/galaxy etc
-	if isSentFrom(lowerLine) {
-		return true
+	if p.isSentFrom(lowerLine) {
+		return RuleSentFrom
 	}
 
-	return false
+	return ""
 }
 
-func detectQuotedEmailStart(lineIndex int, line *Line, lines []*Line) (bool, bool) {
+// detectQuotedEmailStart returns if the line starts a quoted email and if the header is on this single line,
+// it is computed once for all lines of the email
+func (p *Parser) detectQuotedEmailStart(lineIndex int, line *Line, lines []*Line) (bool, bool) {
+	if line.features == nil || lines[lineIndex] != line {
+		return p.computeQuotedEmailStart(lineIndex, line, lines)
+	}
+	starts := p.quoteStartsOf(featuresOf(lines), lines)
+	return starts.multi[lineIndex], starts.single[lineIndex]
+}
+
+func (p *Parser) computeQuotedEmailStart(lineIndex int, line *Line, lines []*Line) (bool, bool) {
+	// -----Original Message-----
+	// From: John Smith [mailto:john@smith.org]
+	if p.blockQuoteHeaderLength(lineIndex, lines) > 0 {
+		return true, false
+	}
+
 	// Detect by quoted reply headers
 	// sometimes there are line breaks within the quoted reply header
 	_, after := lineBeforeAndAfter(lineIndex, lines)
-	lineWithBreaksInOneLine := strings.ToLower(removeEnters(joinLineContents("", line, after)))
+	lineIsQuoteStart := p.isQuotedEmailStart(foldCase(line.ContentStripped))
+
+	// the header starts on the next line e.g. after a reply which was on the same line as the header
+	if after != nil && !lineIsQuoteStart && p.isQuotedEmailStart(foldCase(after.ContentStripped)) {
+		return false, false
+	}
+	lineWithBreaksInOneLine := foldCase(removeEnters(joinLineContents("", line, after)))
 
-	multi := isQuotedEmailStart(lineWithBreaksInOneLine)
-	single := isQuotedEmailStart(strings.ToLower(line.ContentStripped)) && after != nil && containsQuotedEmail(after.ContentStripped)
+	// headers which end with a colon do not match when the next line is added
+	multi := p.isQuotedEmailStart(lineWithBreaksInOneLine) || lineIsQuoteStart
+	single := p.isQuotedEmailStart(foldCase(line.ContentStripped)) && after != nil && containsQuotedEmail(after.ContentStripped)
 	if after != nil && containsQuotedEmail(after.ContentStripped) {
 		single = false
 	}
@@ -201,37 +372,43 @@ func isValidSignatureFormat(fullLine string) bool {
 	return strings.TrimSpace(fullLine) == "--"
 }
 
-func detectSignature(lineIndex int, line *Line, lines []*Line) bool {
+// detectSignature returns the percentage of the lines after a possible signature start which look like a signature
+func (p *Parser) detectSignature(lineIndex int, line *Line, lines []*Line) (float64, bool) {
 	// signatures mostly contains of numbers and short kind of labels with numbers after it
 	// so we try to detect these kind of lines
-	possible := isPossibleSignatureLine(line.ContentStripped)
-
-	if possible {
-		var matches int
-		var lastMatchLineIndex int
-		linesTillQuotedText := getLinesTillQuotedText(lineIndex, lines)
-		for i, signatureLine := range linesTillQuotedText {
-			if isPossibleSignatureLine(signatureLine.ContentStripped) {
-				lastMatchLineIndex = i
-				matches++
-			}
-		}
+	if !line.PossibleSignatureLine {
+		return 0, false
+	}
 
-		// disclaimer
-		possibleDisclaimer := getLinesTillQuotedText(lineIndex+lastMatchLineIndex+1, lines)
-		filledDisclaimerLines := countLinesFilled(possibleDisclaimer)
-		isDisclaimer := filledDisclaimerLines < 6
+	// the lines after this line till the quoted text
+	f := featuresOf(lines)
+	start := lineIndex + 1
+	end := p.nextQuoteStart(f, lineIndex, lines)
+	if start > end {
+		start = end
+	}
+	matches := f.possibleSignature[end] - f.possibleSignature[start]
+	lastMatchLineIndex := start
+	if last := f.lastPossibleSignature[end]; last >= start {
+		lastMatchLineIndex = last
+	}
 
-		filledLines := countLinesFilled(linesTillQuotedText)
-		if isDisclaimer {
-			filledLines -= filledDisclaimerLines
-		}
+	// disclaimer
+	disclaimerStart := lastMatchLineIndex + 1
+	disclaimerEnd := p.nextQuoteStart(f, lastMatchLineIndex, lines)
+	if disclaimerStart > disclaimerEnd {
+		disclaimerStart = disclaimerEnd
+	}
+	filledDisclaimerLines := f.filled[disclaimerEnd] - f.filled[disclaimerStart]
+	isDisclaimer := filledDisclaimerLines < p.maxDisclaimerLines
 
-		percentMatched := (float64(matches) * 100) / float64(filledLines)
-		return percentMatched > 70
+	filledLines := f.filled[end] - f.filled[start]
+	if isDisclaimer {
+		filledLines -= filledDisclaimerLines
 	}
 
-	return false
+	percentMatched := (float64(matches) * 100) / float64(filledLines)
+	return percentMatched, true
 }
 
 func countLinesFilled(lines []*Line) int {
@@ -244,47 +421,32 @@ func countLinesFilled(lines []*Line) int {
 	return count
 }
 
-func detectGreetings(line string) bool {
+func (p *Parser) detectGreetings(line string) bool {
 	// greetings but not
-	if startWithOneOfButDoesNotContainMuchAfter(line, greetings, 2) {
+	if p.startsWithGreeting(line) {
 		return true
 	}
 
 	// without first word e.g. best regards or
 	// -->met<-- vriendelijke groeten
-	if startWithOneOfButDoesNotContainMuchAfter(removeFirstWord(line), greetings, 2) {
+	if p.startsWithGreeting(removeFirstWord(line)) {
 		return true
 	}
 	return false
 }
 
-func removeFirstWord(sentence string) string {
-	split := strings.Split(sentence, space)
-	var a []string
-	for i, w := range split {
-		if i > 0 {
-			a = append(a, w)
-		}
-	}
-	return strings.Join(a, space)
+// startsWithGreeting tells if the line is a greeting with at most two characters after it like regards,
+func (p *Parser) startsWithGreeting(line string) bool {
+	length := p.greetings.prefixLength(line)
+	return length > 0 && len(line)-length <= 2
 }
 
-func getLinesTillQuotedText(lineIndex int, lines []*Line) []*Line {
-	var a []*Line
-
-	for i, line := range lines {
-		if i > lineIndex {
-			multilineQuoteEmailStart, _ := detectQuotedEmailStart(i, line, lines)
-			if multilineQuoteEmailStart {
-				break
-			}
-			a = append(a, line)
-		}
-	}
-	return a
+func removeFirstWord(sentence string) string {
+	_, rest, _ := strings.Cut(sentence, space)
+	return rest
 }
 
-func isPossibleSignatureLine(sentence string) bool {
+func (p *Parser) isPossibleSignatureLine(sentence string) bool {
 	if isName(sentence) {
 		return true
 	}
@@ -302,16 +464,16 @@ func isPossibleSignatureLine(sentence string) bool {
 	if areStripes(sentence) {
 		return true
 	}
-	if isLabelWithValue(noSpaceBetweenNumbers) {
+	if p.isLabelWithValue(noSpaceBetweenNumbers) {
 		return true
 	}
 	if isNumberSignature(noSpaceBetweenNumbers) {
 		return true
 	}
-	if isEmailSignature(sentence) {
+	if p.isEmailSignature(sentence) {
 		return true
 	}
-	if isWebsiteSignature(sentence) {
+	if p.isWebsiteSignature(sentence) {
 		return true
 	}
 	return false
@@ -322,18 +484,19 @@ func isLogo(sentence string) bool {
 }
 
 func removeSpacesBetweenNumbers(sentence string) string {
-	var newSentence string
+	var newSentence strings.Builder
+	newSentence.Grow(len(sentence))
 	split := strings.Split(sentence, space)
 	for i, word := range split {
 		if i > 0 {
 			prevWord := split[i-1]
 			if !isNumberWord(prevWord) {
-				newSentence += space
+				newSentence.WriteString(space)
 			}
 		}
-		newSentence += word
+		newSentence.WriteString(word)
 	}
-	return newSentence
+	return newSentence.String()
 }
 
 func isNumberWord(s string) bool {
@@ -345,14 +508,14 @@ func isNumberWord(s string) bool {
 	return true
 }
 
-func isWebsiteSignature(sentence string) bool {
+func (p *Parser) isWebsiteSignature(sentence string) bool {
 	spaces := strings.Count(sentence, space)
-	return containsWebsite(sentence) && spaces <= 2
+	return spaces <= 2 && p.containsWebsite(sentence)
 }
 
-func isEmailSignature(sentence string) bool {
+func (p *Parser) isEmailSignature(sentence string) bool {
 	spaces := strings.Count(sentence, space)
-	return containsEmail(sentence) && spaces <= 2
+	return spaces <= 2 && p.containsEmail(sentence)
 }
 
 func isNumberSignature(sentence string) bool {
@@ -370,9 +533,9 @@ func areStripes(sentence string) bool {
 	return len(sentence) > 0
 }
 
-func isLabelWithValue(v string) bool {
+func (p *Parser) isLabelWithValue(v string) bool {
 	// is a telephone number with label or some other stuff
-	lowerLine := strings.ToLower(v)
+	lowerLine := foldCase(v)
 	withoutLabel := removeFirstWord(lowerLine)
 
 	amountOfSpaces := strings.Count(withoutLabel, space)
@@ -380,39 +543,24 @@ func isLabelWithValue(v string) bool {
 	// Beatrixlaan 2, 4694EG Scherpenisse
 	// if amountOfCommas >
 
-	return amountOfSpaces <= 1 && (containsEmail(withoutLabel) ||
-		containsWebsite(withoutLabel) ||
+	// a known label like tel: can be followed by a longer value like +31 (0)6 12345678 (mobile)
+	maxSpaces := 1
+	if p.isSignatureLabel(lowerLine) {
+		maxSpaces = maxLabelValueSpaces
+	}
+
+	return amountOfSpaces <= maxSpaces && (p.containsEmail(withoutLabel) ||
+		p.containsWebsite(withoutLabel) ||
 		isNumberSignature(withoutLabel))
 }
 
-func containsWebsite(v string) bool {
-	words := strings.Split(v, space)
-	for _, word := range words {
-		containsSlashes := strings.Count(word, "/")
-		containsDots := strings.Count(word, ".")
-		containsHttp := strings.Count(word, "http")
-		containsWww := strings.Count(word, "www")
-
-		containsExtension := hasOneOf(word, extensions, &dot, nil)
+// maxLabelValueSpaces is the maximum amount of spaces in the value after a known signature label
+const maxLabelValueSpaces = 3
 
-		count := containsSlashes + containsDots + containsHttp + containsWww
-
-		if count >= 1 && containsExtension {
-			return true
-		}
-	}
-	return false
-}
-
-func containsEmail(v string) bool {
-	words := strings.Split(v, space)
-	for _, word := range words {
-		if strings.Contains(word, "@") &&
-			strings.Contains(word, ".") {
-			return true
-		}
-	}
-	return false
+// isSignatureLabel tells if the folded line starts with a signature label which is a whole word
+func (p *Parser) isSignatureLabel(lowerLine string) bool {
+	length := p.signatureLabels.prefixLength(lowerLine)
+	return length > 0 && isWordBoundary(lowerLine, length)
 }
 
 func containsNumber(v string) bool {
@@ -504,22 +652,20 @@ func isFirstLetterUppercase(v string) bool {
 	return false
 }
 
-func isSentFrom(fullLine string) bool {
-	startsWithSend := startWithOneOf(fullLine, sent, true)
-	containsDevice := hasOneOf(fullLine, mailPrograms, nil, nil)
+func (p *Parser) isSentFrom(fullLine string) bool {
+	startsWithSend := p.sent.hasPrefixWord(fullLine)
+	containsDevice := p.mailPrograms.contains(fullLine)
 	return startsWithSend && containsDevice
 }
 
-var spaceStr = " "
-
-func isQuotedEmailStart(fullLine string) bool {
+func (p *Parser) isQuotedEmailStart(fullLine string) bool {
 	// on ... wrote etc
 	// On Monday, November 4, 2013 4:29 PM, John Smith <john.smith@example.org> wrote:
 	// Op za 8 mei 2021 om 12:09 schreef Richard Lindhout <richardlindhout96@gmail.com>:
 	// On Oct 1, 2012, at 11:55 PM, Dave Tapley wrote:
 	// 2013/11/1 John Smith <john@smith.org>
-	startsWithOn := startWithOneOf(fullLine, on, true)
-	containsWrote := hasOneOf(fullLine, wrote, &spaceStr, nil)
+	startsWithOn := p.on.hasPrefixWord(fullLine)
+	containsWrote := p.wrote.contains(fullLine)
 	allNumbers := findNumbers(fullLine)
 	containsYear := numberArrayContainsYear(allNumbers)
 	containsEnoughNumbers := len(allNumbers) >= 3
@@ -533,6 +679,31 @@ func isQuotedEmailStart(fullLine string) bool {
 	} else if containsQuotedEmail && containsEnoughNumbers && containsYear {
 		return true
 	}
+
+	// John Smith/ORG@ORG wrote on 11/04/2013 04:29:12 PM:
+	// John Smith wrote on 04-11-2013 16:29:
+	endsWithColon := strings.HasSuffix(strings.TrimSpace(fullLine), ":")
+	if endsWithColon && p.containsWroteOn(fullLine) && containsEnoughNumbers && containsYear {
+		return true
+	}
+
+	// Quoting John Smith <john@smith.org>:
+	// an address is needed so a line like Quoting the release notes: stays in the reply
+	if endsWithColon && p.quoting.hasPrefixWord(fullLine) && (containsQuotedEmail || p.containsEmail(fullLine)) {
+		return true
+	}
+	return false
+}
+
+// containsWroteOn detects the wrote on in NAME wrote on DATE:
+func (p *Parser) containsWroteOn(fullLine string) bool {
+	for _, wrote := range p.language.Wrote {
+		for _, on := range p.language.On {
+			if strings.Contains(fullLine, space+foldCase(wrote)+space+foldCase(on)+space) {
+				return true
+			}
+		}
+	}
 	return false
 }
 
@@ -568,47 +739,6 @@ func numberArrayContainsYear(a []string) bool {
 	return false
 }
 
-func hasOneOf(value string, a []string, addFront *string, addBack *string) bool {
-	for _, c := range a {
-		finalContains := strings.ToLower(c)
-		if addFront != nil {
-			finalContains = *addFront + finalContains
-		}
-		if addBack != nil {
-			finalContains += *addBack
-		}
-		if strings.Contains(value, finalContains) {
-			return true
-		}
-	}
-	return false
-}
-
-func startWithOneOf(value string, a []string, addSpaceAfter bool) bool {
-	for _, prefix := range a {
-		finalPrefix := strings.ToLower(prefix)
-		if addSpaceAfter {
-			finalPrefix += space
-		}
-		if strings.HasPrefix(value, finalPrefix) {
-			return true
-		}
-	}
-	return false
-}
-
-func startWithOneOfButDoesNotContainMuchAfter(value string, a []string, maxCharactersAfter int) bool {
-	for _, prefix := range a {
-		finalPrefix := strings.ToLower(prefix)
-
-		if strings.HasPrefix(value, finalPrefix) {
-			after := strings.TrimPrefix(value, finalPrefix)
-			return len(after) <= maxCharactersAfter
-		}
-	}
-	return false
-}
-
 // isWhitespace returns true if the string consist of white space
 func isWhitespace(content string) bool {
 	// If the node is a space it's an enter
@@ -621,34 +751,30 @@ func isWhitespace(content string) bool {
 	return true
 }
 
-// removeWhitespace removes all double spaces in text
-func removeWhitespace(v string) (r string) {
-	r = strings.ReplaceAll(v, "\t", space)
-	r = strings.ReplaceAll(r, `	`, space)
-	r = strings.ReplaceAll(r, "  ", space)
-	r = strings.ReplaceAll(r, `  `, space)
-	if v != r {
-		r = removeWhitespace(r)
+// removeWhitespace replaces tabs and runs of spaces with a single space and trims the result
+func removeWhitespace(v string) string {
+	var b strings.Builder
+	b.Grow(len(v))
+	afterSpace := false
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if c == ' ' || c == '\t' {
+			if !afterSpace {
+				b.WriteByte(' ')
+			}
+			afterSpace = true
+			continue
+		}
+		afterSpace = false
+		b.WriteByte(c)
 	}
-	return strings.TrimSpace(r)
+	return strings.TrimSpace(b.String())
 }
 
-func removeEnters(v string) (r string) {
-	r = strings.ReplaceAll(v, enter, "")
-	if v != r {
-		r = removeEnters(r)
-	}
-	return strings.TrimSpace(r)
+func removeEnters(v string) string {
+	return strings.TrimSpace(strings.ReplaceAll(v, enter, ""))
 }
 
-func removeWhiteSpaceBeforeAndAfter(v string) (r string) {
-	r = strings.TrimSpace(v)
-	r = strings.TrimSuffix(r, enter)
-	r = strings.TrimPrefix(r, enter)
-	r = strings.TrimSpace(r)
-
-	if v != r {
-		r = removeWhiteSpaceBeforeAndAfter(r)
-	}
-	return r
+func removeWhiteSpaceBeforeAndAfter(v string) string {
+	return strings.TrimSpace(v)
 }
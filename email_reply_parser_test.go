@@ -15,9 +15,11 @@ func TestIsQuotedEmailStart(t *testing.T) {
 		"2013/11/1 John Smith <john@smith.org>",
 		"On Monday, November 4, 2013 4:29 PM, John Smith <john.smith@example.org> wrote:",
 		"on mon, aug 26, 2019 at 4:37 pm the hiring engine <a-really-long-automated-email+1234556@humanresources.com> wrote:",
+		"Quoting John Smith <john@smith.org>:",
+		"Quoting john@smith.org:",
 	}
 	for _, should := range shouldReturnTrue {
-		if isQuotedEmailStart(strings.ToLower(should)) != true {
+		if defaultParser().isQuotedEmailStart(strings.ToLower(should)) != true {
 			t.Errorf("Should return true: %v", should)
 		}
 	}
@@ -25,9 +27,10 @@ func TestIsQuotedEmailStart(t *testing.T) {
 	shouldReturnFalse := []string{
 		"since on Monday, November 4, John Smith wrote me this message",
 		"You see this this the problem",
+		"Quoting the release notes:",
 	}
 	for _, should := range shouldReturnFalse {
-		if isQuotedEmailStart(strings.ToLower(should)) != false {
+		if defaultParser().isQuotedEmailStart(strings.ToLower(should)) != false {
 			t.Errorf("Should return false: %v", should)
 		}
 	}
@@ -100,7 +103,7 @@ func TestPossibleSignature(t *testing.T) {
 		// TODO: address lines
 	}
 	for _, should := range shouldReturnTrue {
-		if isPossibleSignatureLine(should) != true {
+		if defaultParser().isPossibleSignatureLine(should) != true {
 			t.Errorf("Should return true: %v", should)
 		}
 	}
@@ -117,7 +120,7 @@ func TestPossibleSignature(t *testing.T) {
 		"You see this this the problem",
 	}
 	for _, should := range shouldReturnFalse {
-		if isPossibleSignatureLine(should) != false {
+		if defaultParser().isPossibleSignatureLine(should) != false {
 			t.Errorf("Should return false: %v", should)
 		}
 	}
@@ -131,7 +134,7 @@ func TestGreetings(t *testing.T) {
 		"groeten",
 	}
 	for _, should := range shouldReturnTrue {
-		if detectGreetings(should) != true {
+		if defaultParser().detectGreetings(should) != true {
 			t.Errorf("Should return true: %v", should)
 		}
 	}
@@ -141,12 +144,25 @@ func TestGreetings(t *testing.T) {
 		"You see this this the problem",
 	}
 	for _, should := range shouldReturnFalse {
-		if detectGreetings(should) != false {
+		if defaultParser().detectGreetings(should) != false {
 			t.Errorf("Should return false: %v", should)
 		}
 	}
 }
 
+func TestSignatureLabelValue(t *testing.T) {
+	tests := map[string]bool{
+		// a known label can be followed by a value with a few spaces
+		"Tel: +31 (0)6 1234 5678 (mobile)":        true,
+		"Phone us at +31 (0)6 1234 5678 (mobile)": false,
+	}
+	for line, expected := range tests {
+		if isLabel := defaultParser().isPossibleSignatureLine(line); isLabel != expected {
+			t.Errorf("%v: expected: `%v` but is `%v`", line, expected, isLabel)
+		}
+	}
+}
+
 func TestRemoveSpacesBetweenNumbers(t *testing.T) {
 	before := "Mijn nummer is 0166 66 42 42 45 67"
 	after := "Mijn nummer is 01666642424567"
@@ -185,7 +201,7 @@ Lorem Ipsum is simply dummy text of the printing and typesetting industry. Lorem
 `
 
 func TestRichardSignature(t *testing.T) {
-	if isQuoteOnTop(richardMail) {
+	if defaultParser().isQuoteOnTop(richardMail) {
 		t.Errorf("quote starts on bottom")
 	}
 	content := Parse(richardMail)
@@ -212,7 +228,7 @@ Beatrixlaan 2, 4694EG Scherpenisse
 `
 
 func TestRichardReverseSignature(t *testing.T) {
-	if !isQuoteOnTop(richardReverseMail) {
+	if !defaultParser().isQuoteOnTop(richardReverseMail) {
 		t.Errorf("quote starts on top")
 	}
 	content := Parse(richardReverseMail)
@@ -223,8 +239,8 @@ func TestRichardReverseSignature(t *testing.T) {
 }
 
 func TestMultilineOrSingleLine(t *testing.T) {
-	lines := plainMailToLines(multilineStartQuoteReply)
-	multi, single := detectQuotedEmailStart(0, lines[0], lines)
+	lines := defaultParser().plainMailToLines(multilineStartQuoteReply)
+	multi, single := defaultParser().detectQuotedEmailStart(0, lines[0], lines)
 	if !multi {
 		t.Errorf("Should be multi")
 	}
@@ -417,3 +433,51 @@ func TestAllKindOfCombinations(t *testing.T) {
 
 	t.Logf("%v/%v were successfully parsed", howManySuccess, howManyCombinations)
 }
+
+func TestQuotedBlockWithoutHeader(t *testing.T) {
+	content := Parse(quotedBlockWithoutHeaderMail)
+	expected := "Yes, the deploy is done.\n\n> Did you check the logs?\n\nThe logs are clean."
+	if content != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, content)
+	}
+
+	email := ParseEmail(quotedBlockWithoutHeaderMail)
+	last := email.Fragments[len(email.Fragments)-1]
+	if last.Kind != FragmentQuotedReply || !strings.HasPrefix(last.Content, "> Hi John") {
+		t.Errorf("expected the quoted reply `%v` but is `%v %v`", "> Hi John", last.Kind, last.Content)
+	}
+}
+
+const quotedBlockWithoutHeaderMail = `Yes, the deploy is done.
+
+> Did you check the logs?
+
+The logs are clean.
+
+> Hi John,
+>
+> Is the deploy done?
+> Did you check the logs?
+
+`
+
+func TestSingleQuotedLineAtTheEnd(t *testing.T) {
+	mail := "I agree with this part:\n\n> we should ship on Monday"
+	if Parse(mail) != mail {
+		t.Errorf("expected: `%v` but is `%v`", mail, Parse(mail))
+	}
+}
+
+func TestIsTrailingQuotedBlock(t *testing.T) {
+	lines := defaultParser().plainMailToLines("Hi\n> a\n> b\n\nx\n> c\n> d\n\n")
+	expected := []bool{false, false, false, false, false, true, false, false, false}
+	for i, e := range expected {
+		if is := isTrailingQuotedBlock(i, lines); is != e {
+			t.Errorf("%v: expected: `%v` but is `%v`", i, e, is)
+		}
+	}
+	// the classifiers look at the lines before a forward or the quoted text
+	if !isTrailingQuotedBlock(1, lines[:4]) {
+		t.Errorf("expected the quoted lines to end the first lines")
+	}
+}
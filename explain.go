@@ -0,0 +1,138 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is the name of the rule which decided the kind of a line
+type Rule string
+
+const (
+	// RuleReply is a line before the signature or quoted reply
+	RuleReply Rule = "reply"
+	// RuleSignatureSeparator is the -- line before a signature
+	RuleSignatureSeparator Rule = "signature separator"
+	// RuleGreeting is a greeting like best regards
+	RuleGreeting Rule = "greeting"
+	// RuleSignatureLines is a line after which most lines look like a signature
+	RuleSignatureLines Rule = "signature lines"
+	// RuleSentFrom is a line like Sent from my iPhone
+	RuleSentFrom Rule = "sent from"
+	// RuleAfterSignatureStart is a line after the start of the signature
+	RuleAfterSignatureStart Rule = "after signature start"
+	// RuleDisclaimer is a paragraph after the signature
+	RuleDisclaimer Rule = "disclaimer"
+	// RuleQuoteHeader is a line like On DATE, NAME <EMAIL> wrote:
+	RuleQuoteHeader Rule = "quote header"
+	// RuleQuoteHeaderContinued is the second line of a quote header which is broken over two lines
+	RuleQuoteHeaderContinued Rule = "quote header continued"
+	// RuleQuoted is a line after a quote header or a > line after the signature
+	RuleQuoted Rule = "quoted"
+	// RuleQuotedBlock is a run of > lines at the end of the mail without quote header
+	RuleQuotedBlock Rule = "quoted block"
+	// RuleQuotedBeforeReply is a line of a quoted reply on top of the reply
+	RuleQuotedBeforeReply Rule = "quoted before reply"
+	// RuleInterleavedAnswer is a line below a quoted part of an interleaved reply
+	RuleInterleavedAnswer Rule = "interleaved answer"
+	// RuleForwardMarker is a line like ---------- Forwarded message ----------
+	RuleForwardMarker Rule = "forward marker"
+	// RuleForwardHeader is a From, Date, Subject or To line below the forward marker
+	RuleForwardHeader Rule = "forward header"
+	// RuleForwarded is a line of the forwarded message
+	RuleForwarded Rule = "forwarded"
+	// RuleHTMLQuote is a line in a HTML quote container like <blockquote type="cite">
+	RuleHTMLQuote Rule = "html quote"
+	// RuleHTMLQuoteHeader is a line in a HTML quote header container like <div class="gmail_attr">
+	RuleHTMLQuoteHeader Rule = "html quote header"
+	// RuleHTMLSignature is a line in a HTML signature container like <div class="gmail_signature">
+	RuleHTMLSignature Rule = "html signature"
+)
+
+// LineExplanation tells what kind a line got and which rule decided it
+type LineExplanation struct {
+	Line *Line
+	Kind FragmentKind
+	Rule Rule
+	// SignatureMatchChecked is true when the signature detection calculated
+	// the SignatureMatchPercentage for this line, also when it was too low
+	SignatureMatchChecked    bool
+	SignatureMatchPercentage float64
+}
+
+// Explanation is the classification of every line of an email
+type Explanation struct {
+	// QuoteOnTop is true when the layout is LayoutBottomPosted
+	QuoteOnTop bool
+	IsForward  bool
+	Layout     Layout
+	Lines      []*LineExplanation
+}
+
+// Explain returns every line of the email with its kind and the rule which decided it
+func Explain(plainMail string) *Explanation {
+	return defaultParser().Explain(plainMail)
+}
+
+// Explain returns every line of the email with its kind and the rule which decided it
+func (p *Parser) Explain(plainMail string) *Explanation {
+	_, c, _ := p.classify(plainMail)
+	return &Explanation{
+		QuoteOnTop: c.layout == LayoutBottomPosted,
+		IsForward:  c.isForward,
+		Layout:     c.layout,
+		Lines:      c.explanations,
+	}
+}
+
+// String renders the explanation as a table which can be added to bug reports
+func (e *Explanation) String() string {
+	var b strings.Builder
+	quoteOn := "bottom"
+	if e.QuoteOnTop {
+		quoteOn = "top"
+	}
+	fmt.Fprintf(&b, "quote on %v, layout: %v, forward: %v\n", quoteOn, e.Layout, e.IsForward)
+	for _, explanation := range e.Lines {
+		rule := string(explanation.Rule)
+		if explanation.SignatureMatchChecked {
+			rule += fmt.Sprintf(" (%.0f%% signature)", explanation.SignatureMatchPercentage)
+		}
+		fmt.Fprintf(&b, "%4d %-17v %-40v | %v\n",
+			explanation.Line.Index,
+			explanation.Kind,
+			rule,
+			explanation.Line.Content,
+		)
+	}
+	return b.String()
+}
+
+// classification keeps the kind of every line and why it got that kind
+type classification struct {
+	layout       Layout
+	isForward    bool
+	explanations []*LineExplanation
+}
+
+func newClassification(lines []*Line) *classification {
+	c := &classification{explanations: make([]*LineExplanation, len(lines))}
+	for i, line := range lines {
+		c.explanations[i] = &LineExplanation{Line: line}
+	}
+	return c
+}
+
+func (c *classification) set(lineIndex int, kind FragmentKind, rule Rule) {
+	c.explanations[lineIndex].Kind = kind
+	c.explanations[lineIndex].Rule = rule
+}
+
+func (c *classification) setSignatureMatch(lineIndex int, percentage float64) {
+	c.explanations[lineIndex].SignatureMatchChecked = true
+	c.explanations[lineIndex].SignatureMatchPercentage = percentage
+}
+
+func (c *classification) kind(lineIndex int) FragmentKind {
+	return c.explanations[lineIndex].Kind
+}
@@ -0,0 +1,63 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplainKaren(t *testing.T) {
+	explanation := Explain(karenMail)
+	if explanation.QuoteOnTop || explanation.IsForward {
+		t.Errorf("expected quote on bottom without forward but is `%v`", explanation)
+	}
+	if len(explanation.Lines) != strings.Count(karenMail, "\n")+1 {
+		t.Errorf("expected an explanation for every line but got `%v`", len(explanation.Lines))
+	}
+
+	reply := explanation.Lines[1]
+	if reply.Kind != FragmentReply || reply.Rule != RuleReply {
+		t.Errorf("expected: `%v` but is `%v %v`", "reply reply", reply.Kind, reply.Rule)
+	}
+
+	signatureStart := explanation.Lines[3]
+	if signatureStart.Line.ContentStripped != "Karen The Green" {
+		t.Errorf("expected: `%v` but is `%v`", "Karen The Green", signatureStart.Line.ContentStripped)
+	}
+	if signatureStart.Kind != FragmentSignature || signatureStart.Rule != RuleSignatureLines {
+		t.Errorf("expected: `%v` but is `%v %v`", "signature signature lines", signatureStart.Kind, signatureStart.Rule)
+	}
+	if !signatureStart.SignatureMatchChecked || signatureStart.SignatureMatchPercentage <= 70 {
+		t.Errorf("expected a signature match above 70 but is `%v`", signatureStart.SignatureMatchPercentage)
+	}
+	if explanation.Lines[4].Rule != RuleAfterSignatureStart {
+		t.Errorf("expected: `%v` but is `%v`", RuleAfterSignatureStart, explanation.Lines[4].Rule)
+	}
+	if explanation.Lines[13].Kind != FragmentDisclaimer || explanation.Lines[13].Rule != RuleDisclaimer {
+		t.Errorf("expected: `%v` but is `%v`", RuleDisclaimer, explanation.Lines[13].Rule)
+	}
+	if explanation.Lines[15].Rule != RuleQuoted {
+		t.Errorf("expected: `%v` but is `%v`", RuleQuoted, explanation.Lines[15].Rule)
+	}
+}
+
+func TestExplainMatchesParseEmail(t *testing.T) {
+	for _, mail := range []string{karenMail, richardReverseMail, deviceFooterMail} {
+		explanation := Explain(mail)
+		var kinds []FragmentKind
+		for _, line := range explanation.Lines {
+			if !line.Line.IsEmpty && (len(kinds) == 0 || kinds[len(kinds)-1] != line.Kind) {
+				kinds = append(kinds, line.Kind)
+			}
+		}
+		assertFragmentKinds(t, ParseEmail(mail), kinds)
+	}
+}
+
+func TestExplainString(t *testing.T) {
+	explanation := Explain(deviceFooterMail).String()
+	for _, expected := range []string{"quote on bottom", string(RuleSentFrom), string(RuleQuoteHeader), "Sent from my iPhone"} {
+		if !strings.Contains(explanation, expected) {
+			t.Errorf("expected `%v` in `%v`", expected, explanation)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+// features keeps what the rules need to know about the lines of one email, it is computed once
+// so the rules can look it up instead of scanning the rest of the email again for every line
+type features struct {
+	// filled and possibleSignature count the lines before every index which are not empty
+	// and which look like a signature line
+	filled            []int
+	possibleSignature []int
+	// quoted and unquotedFilled count the quoted lines and the lines which are not quoted and not empty
+	quoted         []int
+	unquotedFilled []int
+	// lastPossibleSignature is the index of the last line before every index which looks like
+	// a signature line or -1
+	lastPossibleSignature []int
+	// quoteStarts are computed for every amount of lines a classifier looks at,
+	// a classifier only looks at the lines before a forward or the quoted text
+	quoteStarts map[int]*quoteStarts
+	// headers are the header runs of the lines, nil for lines which were not split by plainMailToLines
+	headers *headerRuns
+	// done is closed when the parse is canceled, the rest of the work is skipped then
+	done <-chan struct{}
+}
+
+// quoteStarts is the result of detectQuotedEmailStart for every line and the index of the next quote start
+type quoteStarts struct {
+	multi  []bool
+	single []bool
+	// next is the index of the first quote start after every index or the amount of lines
+	next []int
+}
+
+// headerRuns are the From:, To:, Sent: and other header lines of an email, they are found in one pass
+// so a header which starts at a line is looked up instead of reading the lines below it again
+type headerRuns struct {
+	// length is the amount of lines a header which starts at the line uses like parseMessageHeader
+	// or 0 when the line is no header line
+	length []int
+	// fieldCounts counts the header lines of every field before every index
+	fieldCounts [headerDate + 1][]int
+}
+
+// newFeatures computes the features of the lines
+func newFeatures(lines []*Line) *features {
+	f := &features{
+		filled:                make([]int, len(lines)+1),
+		possibleSignature:     make([]int, len(lines)+1),
+		quoted:                make([]int, len(lines)+1),
+		unquotedFilled:        make([]int, len(lines)+1),
+		lastPossibleSignature: make([]int, len(lines)+1),
+		quoteStarts:           map[int]*quoteStarts{},
+	}
+	f.lastPossibleSignature[0] = -1
+	for i, line := range lines {
+		f.filled[i+1] = f.filled[i]
+		f.possibleSignature[i+1] = f.possibleSignature[i]
+		f.lastPossibleSignature[i+1] = f.lastPossibleSignature[i]
+		f.quoted[i+1] = f.quoted[i]
+		f.unquotedFilled[i+1] = f.unquotedFilled[i]
+		if !line.IsEmpty {
+			f.filled[i+1]++
+		}
+		switch {
+		case line.IsQuoted:
+			f.quoted[i+1]++
+		case !line.IsEmpty:
+			f.unquotedFilled[i+1]++
+		}
+		if line.PossibleSignatureLine {
+			f.possibleSignature[i+1]++
+			f.lastPossibleSignature[i+1] = i
+		}
+	}
+	return f
+}
+
+// featuresOf returns the features plainMailToLines computed for the lines, the classifiers only use
+// the lines from the start of the email so the indexes are the same, for other lines they are computed
+func featuresOf(lines []*Line) *features {
+	if len(lines) > 0 && lines[0].Index == 0 && lines[0].features != nil {
+		return lines[0].features
+	}
+	return newFeatures(lines)
+}
+
+// newHeaderRuns finds the header runs of the lines, a header runs till the first empty line
+// and uses at most maxHeaderLines lines
+func (p *Parser) newHeaderRuns(lines []*Line) *headerRuns {
+	runs := &headerRuns{length: make([]int, len(lines))}
+	fields := make([]headerField, len(lines))
+	for field := range runs.fieldCounts {
+		runs.fieldCounts[field] = make([]int, len(lines)+1)
+	}
+	for i, line := range lines {
+		fields[i], _ = p.splitHeaderLine(line.ContentStripped)
+		for field := range runs.fieldCounts {
+			runs.fieldCounts[field][i+1] = runs.fieldCounts[field][i]
+		}
+		runs.fieldCounts[fields[i]][i+1]++
+	}
+	var filled int
+	for i := len(lines) - 1; i >= 0; i-- {
+		filled++
+		if lines[i].IsEmpty {
+			filled = 0
+		}
+		if fields[i] != headerUnknown {
+			runs.length[i] = filled
+			if filled > maxHeaderLines {
+				runs.length[i] = maxHeaderLines
+			}
+		}
+	}
+	return runs
+}
+
+// headerRunsOf returns the header runs of the lines and the index of the first line in them,
+// they are only computed again for lines which were not split by plainMailToLines
+func (p *Parser) headerRunsOf(lines []*Line) (*headerRuns, int) {
+	first, last := lines[0], lines[len(lines)-1]
+	if f := first.features; f != nil && f.headers != nil && last.features == f && last.Index-first.Index == len(lines)-1 {
+		return f.headers, first.Index
+	}
+	return p.newHeaderRuns(lines), 0
+}
+
+// canceled tells if the parse of the lines is canceled, the loops over the lines stop then
+func canceled(lines []*Line) bool {
+	return len(lines) > 0 && lines[0].features != nil && isDone(lines[0].features.done)
+}
+
+// quoteStartsOf returns the quote starts of the lines and computes them the first time
+func (p *Parser) quoteStartsOf(f *features, lines []*Line) *quoteStarts {
+	if starts, ok := f.quoteStarts[len(lines)]; ok {
+		return starts
+	}
+	starts := &quoteStarts{
+		multi:  make([]bool, len(lines)),
+		single: make([]bool, len(lines)),
+		next:   make([]int, len(lines)),
+	}
+	next := len(lines)
+	for i := len(lines) - 1; i >= 0 && !isDone(f.done); i-- {
+		starts.next[i] = next
+		starts.multi[i], starts.single[i] = p.computeQuotedEmailStart(i, lines[i], lines)
+		if starts.multi[i] {
+			next = i
+		}
+	}
+	f.quoteStarts[len(lines)] = starts
+	return starts
+}
+
+// nextQuoteStart returns the index of the first quote start after the line or the amount of lines
+func (p *Parser) nextQuoteStart(f *features, lineIndex int, lines []*Line) int {
+	if lineIndex >= len(lines) {
+		return len(lines)
+	}
+	return p.quoteStartsOf(f, lines).next[lineIndex]
+}
@@ -0,0 +1,99 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+)
+
+// Forward is a forwarded message with the comment the forwarder wrote above it
+type Forward struct {
+	// Comment is what the forwarder wrote above the forwarded message without the signature
+	Comment string
+	// Marker is the line which started the forward e.g. ---------- Forwarded message ----------
+	Marker string
+	// Header is nil when there are no From, Date, Subject or To lines below the marker
+	Header *MessageHeader
+	// Body is the forwarded message
+	Body string
+}
+
+// ParseForward returns the forwarded message or nil when the email is not a forward
+func ParseForward(plainMail string) *Forward {
+	return defaultParser().ParseForward(plainMail)
+}
+
+// ParseForward returns the forwarded message or nil when the email is not a forward
+func (p *Parser) ParseForward(plainMail string) *Forward {
+	return p.ParseEmail(plainMail).Forward
+}
+
+// detectForwardStart returns the index of the line which starts the forwarded message
+// or -1 when the forward is not found before any quoted reply
+func (p *Parser) detectForwardStart(lines []*Line) int {
+	for i, line := range lines {
+		if canceled(lines) {
+			return -1
+		}
+		isQuoteStart, _ := p.detectQuotedEmailStart(i, line, lines)
+		if isQuoteStart {
+			return -1
+		}
+		if p.isForwardStart(i, line, lines) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isForwardStart detects lines like
+// ---------- Forwarded message ----------
+// Begin forwarded message:
+func (p *Parser) isForwardStart(lineIndex int, line *Line, lines []*Line) bool {
+	if line.IsQuoted || line.IsEmpty {
+		return false
+	}
+	stripped := line.ContentStripped
+	marker := foldCase(strings.Trim(stripped, "-_=: "))
+	if !p.forwarded.contains(marker) || strings.Count(marker, space) > 5 {
+		return false
+	}
+
+	// ---------- Forwarded message ---------- and Begin forwarded message: are followed by the header of the
+	// forwarded message with a sender or a date, a sentence like -- forwarded to legal as well is not
+	if !strings.HasPrefix(stripped, "--") && !strings.HasSuffix(stripped, ":") {
+		return false
+	}
+	headerStart := lineIndex + 1
+	for headerStart < len(lines) && headerStart-lineIndex < maxHeaderLines && lines[headerStart].IsEmpty {
+		headerStart++
+	}
+	return p.hasHeaderValues(lines[headerStart:])
+}
+
+// classifyForward marks the forward marker and header and the forwarded message after it
+func (p *Parser) classifyForward(lineIndex int, lines []*Line, c *classification) *Forward {
+	forward := &Forward{Marker: lines[lineIndex].ContentStripped}
+
+	headerStart := lineIndex + 1
+	for headerStart < len(lines) && lines[headerStart].IsEmpty {
+		headerStart++
+	}
+	header, used := p.parseMessageHeader(lines[headerStart:])
+	forward.Header = header
+
+	bodyStart := lineIndex + 1
+	if header != nil {
+		bodyStart = headerStart + used
+	}
+	for i := lineIndex; i < len(lines); i++ {
+		switch {
+		case i == lineIndex:
+			c.set(i, FragmentForwardHeader, RuleForwardMarker)
+		case i < bodyStart:
+			c.set(i, FragmentForwardHeader, RuleForwardHeader)
+		default:
+			c.set(i, FragmentForwardedMessage, RuleForwarded)
+		}
+	}
+	forward.Body = removeWhiteSpaceBeforeAndAfter(joinLines(lines[bodyStart:]))
+	return forward
+}
@@ -0,0 +1,98 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestGmailForward(t *testing.T) {
+	mailContent, err := os.ReadFile("./dataset/forward/gmail.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	forward := ParseForward(string(mailContent))
+	if forward == nil {
+		t.Fatalf("Should be a forward")
+	}
+	if forward.Comment != "Hey, check out the joke below." {
+		t.Errorf("expected: `%v` but is `%v`", "Hey, check out the joke below.", forward.Comment)
+	}
+	if forward.Header == nil {
+		t.Fatalf("Should have a header")
+	}
+	if forward.Header.From == nil || forward.Header.From.Address != "a.h@t.com" {
+		t.Errorf("expected from: `%v` but is `%v`", "a.h@t.com", forward.Header.From)
+	}
+	if len(forward.Header.To) != 1 || forward.Header.To[0].Address != "f@d.com" {
+		t.Errorf("expected to: `%v` but is `%v`", "f@d.com", forward.Header.To)
+	}
+	if forward.Header.Subject != "Programming Joke" {
+		t.Errorf("expected subject: `%v` but is `%v`", "Programming Joke", forward.Header.Subject)
+	}
+	date := time.Date(2013, time.October, 24, 15, 57, 0, 0, time.UTC)
+	if !forward.Header.Date.Equal(date) {
+		t.Errorf("expected date: `%v` but is `%v`", date, forward.Header.Date)
+	}
+	if Parse(string(mailContent)) != forward.Comment {
+		t.Errorf("expected: `%v` but is `%v`", forward.Comment, Parse(string(mailContent)))
+	}
+}
+
+func TestAppleForward(t *testing.T) {
+	forward := ParseForward(appleForwardMail)
+	if forward == nil {
+		t.Fatalf("Should be a forward")
+	}
+	if forward.Comment != "Zie hieronder." {
+		t.Errorf("expected: `%v` but is `%v`", "Zie hieronder.", forward.Comment)
+	}
+	if forward.Header == nil || forward.Header.From == nil || forward.Header.From.Name != "Jan Müller" {
+		t.Fatalf("expected from Jan Müller but is `%v`", forward.Header)
+	}
+	if forward.Header.Subject != "Rechnung" {
+		t.Errorf("expected subject: `%v` but is `%v`", "Rechnung", forward.Header.Subject)
+	}
+	if forward.Body != "Hallo Richard,\n\nanbei die Rechnung." {
+		t.Errorf("expected: `%v` but is `%v`", "Hallo Richard,\n\nanbei die Rechnung.", forward.Body)
+	}
+}
+
+const appleForwardMail = `Zie hieronder.
+
+Met vriendelijke groeten,
+Richard Lindhout
+
+Anfang der weitergeleiteten Nachricht:
+
+*Von:* Jan Müller <jan@example.org>
+*Betreff:* Rechnung
+*Datum:* 4. November 2013 um 16:29:00 MEZ
+*An:* Richard Lindhout <richard@example.org>
+
+Hallo Richard,
+
+anbei die Rechnung.`
+
+func TestNoForward(t *testing.T) {
+	shouldReturnNil := []string{
+		"I forwarded it to you:\n\nthe report",
+		"Hi,\n\nI forwarded the message to Bob.\n-- forwarded to legal as well\nThanks",
+		"Hi,\n\n---------- Forwarded message ----------\nthe report",
+		karenMail,
+		richardReverseMail,
+	}
+	for _, should := range shouldReturnNil {
+		if ParseForward(should) != nil {
+			t.Errorf("Should not be a forward: %v", should)
+		}
+	}
+}
+
+func TestForwardNoteKeepsReply(t *testing.T) {
+	mail := "Hi,\n\nI forwarded the message to Bob.\n-- forwarded to legal as well\nThanks"
+	if reply := Parse(mail); reply != mail {
+		t.Errorf("expected: `%v` but is `%v`", mail, reply)
+	}
+}
@@ -0,0 +1,249 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+)
+
+// FragmentKind tells what a part of an email is used for
+type FragmentKind int
+
+const (
+	// FragmentReply is the text the sender actually wrote
+	FragmentReply FragmentKind = iota
+	// FragmentSignature is e.g. a greeting, a name or contact details
+	FragmentSignature
+	// FragmentQuotedReply is the quoted text of an earlier message
+	FragmentQuotedReply
+	// FragmentQuoteHeader is a line like On DATE, NAME <EMAIL> wrote:
+	FragmentQuoteHeader
+	// FragmentDisclaimer is the legal text below a signature
+	FragmentDisclaimer
+	// FragmentDeviceFooter is a line like Sent from my iPhone
+	FragmentDeviceFooter
+	// FragmentForwardHeader is the line like ---------- Forwarded message ----------
+	// with the From, Date, Subject and To lines below it
+	FragmentForwardHeader
+	// FragmentForwardedMessage is the message which was forwarded
+	FragmentForwardedMessage
+)
+
+//nolint:gochecknoglobals
+var fragmentKindNames = map[FragmentKind]string{
+	FragmentReply:            "reply",
+	FragmentSignature:        "signature",
+	FragmentQuotedReply:      "quoted reply",
+	FragmentQuoteHeader:      "quote header",
+	FragmentDisclaimer:       "disclaimer",
+	FragmentDeviceFooter:     "device footer",
+	FragmentForwardHeader:    "forward header",
+	FragmentForwardedMessage: "forwarded message",
+}
+
+func (k FragmentKind) String() string {
+	if name, ok := fragmentKindNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Fragment is a part of an email with consecutive lines of the same kind
+type Fragment struct {
+	Kind    FragmentKind
+	Content string
+	Lines   []*Line
+	// Span is the position of the Content in the parsed text
+	Span Span
+	// Attribution is only set for quote headers
+	Attribution *Attribution
+	// Header is only set for Outlook quote headers like From: Sent: To: Subject:
+	Header *MessageHeader
+}
+
+// Email is a parsed email split in fragments in the order they appear in the mail
+type Email struct {
+	Fragments []*Fragment
+	// Forward is nil when the email is not a forward
+	Forward *Forward
+	// Layout tells where the reply is written compared to the quoted text, for LayoutInterleaved
+	// Answers contains every answer with the quoted part above it
+	Layout  Layout
+	Answers []*QuoteAnswer
+	// Truncated is set when only the first part of the email is parsed, by ParseContext when the email
+	// exceeds the Limits and by ParseReader when it stopped reading after the quote header
+	Truncated bool
+
+	replyKinds []FragmentKind
+}
+
+// Reply returns the text the sender actually wrote, this is the same as Parse returns
+func (e *Email) Reply() string {
+	return e.Text(e.replyKinds...)
+}
+
+// Text returns the content of all fragments of the given kinds,
+// fragments which were not next to each other are separated by an empty line
+func (e *Email) Text(kinds ...FragmentKind) string {
+	var a []string
+	skipped := false
+	for _, fragment := range e.Fragments {
+		if !isOneOfKinds(fragment.Kind, kinds) {
+			skipped = true
+			continue
+		}
+		if last := len(a) - 1; skipped && last >= 0 && !isWhitespace(a[last]) {
+			// keep the CRLF line endings of the email
+			if strings.HasSuffix(a[last], "\r") {
+				a = append(a, "\r")
+			} else {
+				a = append(a, "")
+			}
+		}
+		skipped = false
+		for _, line := range fragment.Lines {
+			a = append(a, line.Content)
+		}
+	}
+	return removeWhiteSpaceBeforeAndAfter(strings.Join(a, enter))
+}
+
+func isOneOfKinds(kind FragmentKind, kinds []FragmentKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Parser) newEmail(lines []*Line, c *classification) *Email {
+	var fragments []*Fragment
+	var current *Fragment
+	for i, line := range lines {
+		if current == nil || current.Kind != c.kind(i) {
+			current = &Fragment{Kind: c.kind(i)}
+			fragments = append(fragments, current)
+		}
+		current.Lines = append(current.Lines, line)
+	}
+
+	// fragments with only empty lines are not interesting to anyone
+	email := &Email{replyKinds: p.replyKinds}
+	for _, fragment := range fragments {
+		if countLinesFilled(fragment.Lines) == 0 {
+			continue
+		}
+		fragment.Content = removeWhiteSpaceBeforeAndAfter(joinLines(fragment.Lines))
+		fragment.Span = spanOf(fragment.Lines)
+		if fragment.Kind == FragmentQuoteHeader {
+			fragment.Attribution = p.parseAttribution(fragment.Content)
+			if header := p.parseOutlookHeader(fragment.Lines); header != nil {
+				fragment.Header = header
+				fragment.Attribution = attributionFromHeader(header)
+				fragment.Attribution.Raw = fragment.Content
+			}
+		}
+		email.Fragments = append(email.Fragments, fragment)
+	}
+	email.Layout = c.layout
+	if c.layout == LayoutInterleaved {
+		email.Answers = p.quoteAnswers(email.Fragments)
+	}
+	return email
+}
+
+func joinLines(lines []*Line) string {
+	a := make([]string, len(lines))
+	for i, line := range lines {
+		a[i] = line.Content
+	}
+	return strings.Join(a, enter)
+}
+
+// classifySignature marks the lines from the signature start till the quoted text
+// and returns the index of the first line after the signature
+func (p *Parser) classifySignature(lineIndex int, lines []*Line, c *classification, rule Rule) int {
+	end := lineIndex + 1
+	for end < len(lines) && !canceled(lines) {
+		isQuoteStart, _ := p.detectQuotedEmailStart(end, lines[end], lines)
+		if isQuoteStart || lines[end].IsQuoted {
+			break
+		}
+		end++
+	}
+
+	disclaimerStart := p.detectDisclaimerStart(lineIndex, end, lines)
+	for i := lineIndex; i < end; i++ {
+		switch {
+		case p.isSentFrom(foldCase(lines[i].ContentStripped)):
+			c.set(i, FragmentDeviceFooter, RuleSentFrom)
+		case disclaimerStart != -1 && i >= disclaimerStart:
+			c.set(i, FragmentDisclaimer, RuleDisclaimer)
+		case i == lineIndex:
+			c.set(i, FragmentSignature, rule)
+		default:
+			c.set(i, FragmentSignature, RuleAfterSignatureStart)
+		}
+	}
+	return end
+}
+
+// detectDisclaimerStart returns the index of the first line of a disclaimer
+// e.g. the paragraph after the contact details or -1 if there is none
+func (p *Parser) detectDisclaimerStart(lineIndex int, end int, lines []*Line) int {
+	lastMatchLineIndex := lineIndex
+	for i := lineIndex + 1; i < end; i++ {
+		if lines[i].PossibleSignatureLine {
+			lastMatchLineIndex = i
+		}
+	}
+
+	possibleDisclaimer := lines[lastMatchLineIndex+1 : end]
+	filledLines := countLinesFilled(possibleDisclaimer)
+	if filledLines == 0 || filledLines >= p.maxDisclaimerLines {
+		return -1
+	}
+
+	for i, line := range possibleDisclaimer {
+		if line.IsEmpty {
+			continue
+		}
+		// a disclaimer is a separate paragraph
+		if i == 0 {
+			return -1
+		}
+		return lastMatchLineIndex + 1 + i
+	}
+	return -1
+}
+
+// classifyQuote marks the quote header and all lines after it as quoted text
+func (p *Parser) classifyQuote(lineIndex int, lines []*Line, c *classification) {
+	headerEnd := lineIndex
+	isQuoteStart, _ := p.detectQuotedEmailStart(lineIndex, lines[lineIndex], lines)
+	if isQuoteStart {
+		headerEnd += p.quoteHeaderLength(lineIndex, lines)
+	}
+	for i := lineIndex; i < len(lines); i++ {
+		switch {
+		case i == lineIndex && i < headerEnd:
+			c.set(i, FragmentQuoteHeader, RuleQuoteHeader)
+		case i < headerEnd:
+			c.set(i, FragmentQuoteHeader, RuleQuoteHeaderContinued)
+		default:
+			c.set(i, FragmentQuotedReply, RuleQuoted)
+		}
+	}
+}
+
+// quoteHeaderLength returns the amount of lines of an Outlook or wrapped quote header
+// or 2 if the quote header is broken over two lines
+func (p *Parser) quoteHeaderLength(lineIndex int, lines []*Line) int {
+	if length := p.blockQuoteHeaderLength(lineIndex, lines); length > 0 {
+		return length
+	}
+	if lineIndex+1 < len(lines) &&
+		!p.isQuotedEmailStart(foldCase(lines[lineIndex].ContentStripped)) {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,78 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKarenFragments(t *testing.T) {
+	email := ParseEmail(karenMail)
+	expected := []FragmentKind{
+		FragmentReply,
+		FragmentSignature,
+		FragmentDisclaimer,
+		FragmentQuotedReply,
+	}
+	assertFragmentKinds(t, email, expected)
+
+	if email.Reply() != Parse(karenMail) {
+		t.Errorf("expected: `%v` but is `%v`", Parse(karenMail), email.Reply())
+	}
+	if !strings.HasPrefix(email.Fragments[2].Content, "Lorem Ipsum") {
+		t.Errorf("disclaimer should start with Lorem Ipsum but is `%v`", email.Fragments[2].Content)
+	}
+}
+
+func TestRichardReverseFragments(t *testing.T) {
+	email := ParseEmail(richardReverseMail)
+	expected := []FragmentKind{
+		FragmentQuoteHeader,
+		FragmentQuotedReply,
+		FragmentReply,
+		FragmentSignature,
+	}
+	assertFragmentKinds(t, email, expected)
+
+	header := "On Mon, Aug 26, 2019 at 4:37 PM The Hiring Engine <\n" +
+		"a-really-long-automated-email+1234556@humanresources.com> wrote:"
+	if email.Fragments[0].Content != header {
+		t.Errorf("expected: `%v` but is `%v`", header, email.Fragments[0].Content)
+	}
+	if email.Reply() != ":+1:" {
+		t.Errorf("expected: `%v` but is `%v`", ":+1:", email.Reply())
+	}
+}
+
+func TestDeviceFooterFragment(t *testing.T) {
+	email := ParseEmail(deviceFooterMail)
+	expected := []FragmentKind{
+		FragmentReply,
+		FragmentDeviceFooter,
+		FragmentQuoteHeader,
+		FragmentQuotedReply,
+	}
+	assertFragmentKinds(t, email, expected)
+
+	if email.Text(FragmentDeviceFooter) != "Sent from my iPhone" {
+		t.Errorf("expected: `%v` but is `%v`", "Sent from my iPhone", email.Text(FragmentDeviceFooter))
+	}
+}
+
+const deviceFooterMail = `Sounds good, see you tomorrow!
+
+Sent from my iPhone
+
+On Monday, November 4, 2013 4:29 PM, John Smith <john.smith@example.org> wrote:
+> Shall we meet tomorrow?`
+
+func assertFragmentKinds(t *testing.T, email *Email, expected []FragmentKind) {
+	t.Helper()
+	if len(email.Fragments) != len(expected) {
+		t.Fatalf("expected %v fragments but got %v", len(expected), len(email.Fragments))
+	}
+	for i, fragment := range email.Fragments {
+		if fragment.Kind != expected[i] {
+			t.Errorf("fragment %v: expected: `%v` but is `%v`", i, expected[i], fragment.Kind)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// MessageHeader are the From, Date, Subject and To lines above a forwarded or quoted message e.g.
+// From: John Smith <john.smith@example.org>
+// Date: Thu, Oct 24, 2013 at 3:57 PM
+// Subject: Programming Joke
+// To: karen@webby.com
+type MessageHeader struct {
+	From    *mail.Address
+	To      []*mail.Address
+	Cc      []*mail.Address
+	Subject string
+	// Date is zero when the date could not be read
+	Date time.Time
+	// Raw contains the header lines as they were found in the email
+	Raw string
+}
+
+type headerField int
+
+const (
+	headerUnknown headerField = iota
+	headerFrom
+	headerTo
+	headerCc
+	headerSubject
+	headerDate
+)
+
+// splitHeaderLine returns the field and value of a line like From: John Smith <john@smith.org>
+func (p *Parser) splitHeaderLine(line string) (headerField, string) {
+	i := strings.Index(line, ":")
+	if i <= 0 {
+		return headerUnknown, ""
+	}
+	label := foldCase(strings.TrimSpace(line[:i]))
+	value := strings.TrimSpace(line[i+1:])
+	switch {
+	case isOneOf(label, p.language.FromLabels):
+		return headerFrom, value
+	case isOneOf(label, p.language.ToLabels):
+		return headerTo, value
+	case isOneOf(label, p.language.CcLabels):
+		return headerCc, value
+	case isOneOf(label, p.language.SubjectLabels):
+		return headerSubject, value
+	case isOneOf(label, p.language.DateLabels):
+		return headerDate, value
+	}
+	return headerUnknown, ""
+}
+
+func (p *Parser) isHeaderLine(line *Line) bool {
+	field, _ := p.splitHeaderLine(line.ContentStripped)
+	return field != headerUnknown
+}
+
+// maxHeaderLines is the maximum amount of lines of the header above a forwarded or quoted message
+const maxHeaderLines = 30
+
+// parseMessageHeader reads the header lines from the start of the lines till the first empty line
+// and returns the header and the amount of lines used
+func (p *Parser) parseMessageHeader(lines []*Line) (*MessageHeader, int) {
+	header := &MessageHeader{}
+	values := map[headerField]string{}
+	var lastField headerField
+	var used int
+	if len(lines) > maxHeaderLines {
+		lines = lines[:maxHeaderLines]
+	}
+	for _, line := range lines {
+		if line.IsEmpty {
+			break
+		}
+		field, value := p.splitHeaderLine(line.ContentStripped)
+		if field == headerUnknown {
+			// long To: lines are broken over multiple lines
+			if lastField == headerUnknown {
+				break
+			}
+			values[lastField] += space + line.ContentStripped
+		} else if _, seen := values[field]; !seen {
+			values[field] = value
+			lastField = field
+		}
+		used++
+	}
+	if used == 0 {
+		return nil, 0
+	}
+
+	header.Raw = joinLines(lines[:used])
+	header.From = parseLooseAddress(values[headerFrom])
+	header.To = parseLooseAddressList(values[headerTo])
+	header.Cc = parseLooseAddressList(values[headerCc])
+	header.Subject = values[headerSubject]
+	header.Date = p.parseDate(values[headerDate])
+	return header, used
+}
+
+// parseLooseAddress reads addresses like <a.h@t.com>, John Smith <john@smith.org>
+// or the Outlook way John Smith [mailto:john@smith.org]
+func parseLooseAddress(v string) *mail.Address {
+	if address, err := mail.ParseAddress(v); err == nil {
+		return address
+	}
+	location := attributionEmailRegex.FindStringIndex(v)
+	if location == nil {
+		return nil
+	}
+	name := strings.TrimSpace(v[:location[0]])
+	name = strings.TrimSuffix(name, "mailto:")
+	name = strings.Trim(name, `"'<>[]() `)
+	return &mail.Address{Name: name, Address: v[location[0]:location[1]]}
+}
+
+func parseLooseAddressList(v string) []*mail.Address {
+	if strings.TrimSpace(v) == "" {
+		return nil
+	}
+	if addresses, err := mail.ParseAddressList(v); err == nil {
+		return addresses
+	}
+	var addresses []*mail.Address
+	for _, part := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' }) {
+		if address := parseLooseAddress(part); address != nil {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// attributionFromHeader returns the author and date of a forwarded or quoted message from its header
+func attributionFromHeader(header *MessageHeader) *Attribution {
+	attribution := &Attribution{
+		Address: header.From,
+		Raw:     header.Raw,
+		Date:    header.Date,
+	}
+	if header.From != nil {
+		attribution.Name = header.From.Name
+	}
+	return attribution
+}
@@ -0,0 +1,334 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+)
+
+// HTMLReply is a parsed HTML email
+type HTMLReply struct {
+	// HTML is the reply without quoted replies and signatures, only known formatting elements and attributes
+	// are kept so scripts, svg, embedded frames, event handlers like onerror, javascript:, vbscript: and data:
+	// links except data: images and styles with links are left out
+	HTML string
+	// Text is the reply as plain text, this is the same as Email.Reply()
+	Text string
+	// Email contains the fragments of the text of the whole HTML email
+	Email *Email
+}
+
+// ParseHTML returns the reply of a HTML email as cleaned HTML and as text,
+// it uses the quote and signature containers of Gmail, Outlook, Apple Mail and Thunderbird
+// and the plain text rules for everything outside of them
+func ParseHTML(htmlMail string) *HTMLReply {
+	return defaultParser().ParseHTML(htmlMail)
+}
+
+// ParseHTML returns the reply of a HTML email as cleaned HTML and as text
+func (p *Parser) ParseHTML(htmlMail string) *HTMLReply {
+	root := parseHTMLTree(htmlMail)
+	r := &htmlTextRenderer{}
+	r.render(root, htmlContext{kind: -1})
+	r.endLine()
+
+	text, kinds, lineStarts := p.splitRenderedLines(r.text(), r.kinds)
+	lines, c, forward := p.classify(strings.Join(text, enter))
+	for i, kind := range kinds {
+		switch {
+		// Apple Mail puts the quote header in its own quote container
+		case kind == FragmentQuotedReply && c.kind(i) == FragmentQuoteHeader:
+		case kind == FragmentQuotedReply:
+			c.set(i, kind, RuleHTMLQuote)
+		case kind == FragmentQuoteHeader:
+			c.set(i, kind, RuleHTMLQuoteHeader)
+		case kind == FragmentSignature:
+			c.set(i, kind, RuleHTMLSignature)
+		}
+	}
+	email := p.emailFromClassification(lines, c, forward)
+
+	keep := map[*htmlNode]bool{}
+	p.markKeptNodes(root, c, lineStarts, keep)
+	var b strings.Builder
+	body := findHTMLElement(root, "body")
+	if body == nil {
+		body = root
+	}
+	for _, child := range body.children {
+		renderHTML(&b, child, func(n *htmlNode) bool {
+			return !keep[n]
+		})
+	}
+
+	return &HTMLReply{
+		HTML:  trimHTML(b.String()),
+		Text:  email.Reply(),
+		Email: email,
+	}
+}
+
+//nolint:gochecknoglobals
+var (
+	skippedElements = []string{
+		"head", "script", "style", "title", "template",
+		"applet", "base", "embed", "frame", "frameset", "iframe", "link", "meta", "object",
+		"math", "svg",
+	}
+	blockElements = []string{
+		"address", "article", "aside", "blockquote", "center", "dd", "div", "dl", "dt", "fieldset",
+		"figure", "footer", "form", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hr", "li",
+		"main", "nav", "ol", "p", "pre", "section", "table", "tbody", "tfoot", "thead", "tr", "ul",
+	}
+)
+
+// htmlContainer returns the kind an element gives its content, a quote section like the
+// gmail_quote div contains a quote header and the quote so its content is only quoted
+// when it is not in a header
+func htmlContainer(n *htmlNode) (kind FragmentKind, section bool) {
+	switch {
+	case n.hasClass("gmail_signature"), n.hasClass("moz-signature"),
+		n.attr("data-smartmail") == "gmail_signature":
+		return FragmentSignature, false
+	case n.hasClass("gmail_attr"), n.hasClass("moz-cite-prefix"), n.attr("id") == "divRplyFwdMsg":
+		return FragmentQuoteHeader, false
+	case n.tag == "blockquote" && (strings.EqualFold(n.attr("type"), "cite") || n.hasClass("gmail_quote")):
+		return FragmentQuotedReply, false
+	case n.hasClass("gmail_quote"), n.hasClass("yahoo_quoted"), n.attr("id") == "appendonsend":
+		return -1, true
+	}
+	return -1, false
+}
+
+// htmlContext is the quote and signature state of the elements around a node
+type htmlContext struct {
+	// kind is the kind of the outer container or -1
+	kind    FragmentKind
+	section bool
+}
+
+// htmlTextRenderer renders the HTML as plain text and remembers the kind of container every line is in
+type htmlTextRenderer struct {
+	lines  []string
+	depths []int
+	kinds  []FragmentKind
+
+	open         bool
+	pendingSpace bool
+	quoteDepth   int
+	pre          int
+	// afterMarker is set after the Outlook markers, everything after them is quoted
+	afterMarker bool
+}
+
+func (r *htmlTextRenderer) render(n *htmlNode, ctx htmlContext) {
+	if n.tag == "" {
+		r.writeText(n, ctx)
+		return
+	}
+	if isOneOf(n.tag, skippedElements) {
+		return
+	}
+
+	if ctx.kind == -1 {
+		kind, section := htmlContainer(n)
+		ctx.kind = kind
+		ctx.section = ctx.section || section
+	}
+	if n.attr("id") == "appendonsend" {
+		r.afterMarker = true
+	}
+	n.container = r.kindOf(ctx)
+
+	isBlock := isOneOf(n.tag, blockElements)
+	switch n.tag {
+	case "br":
+		r.ensureLine(ctx)
+		r.endLine()
+		return
+	case "blockquote":
+		r.endLine()
+		r.quoteDepth++
+		defer func() { r.quoteDepth-- }()
+	case "pre":
+		r.pre++
+		defer func() { r.pre-- }()
+	case "td", "th":
+		r.pendingSpace = true
+	}
+	if isBlock {
+		r.endLine()
+	}
+	for _, child := range n.children {
+		r.render(child, ctx)
+	}
+	if isBlock {
+		r.endLine()
+	}
+	if n.attr("id") == "divRplyFwdMsg" {
+		r.afterMarker = true
+	}
+}
+
+// kindOf returns the kind of the lines in the context or -1 when the HTML does not tell
+func (r *htmlTextRenderer) kindOf(ctx htmlContext) FragmentKind {
+	if ctx.kind != -1 {
+		return ctx.kind
+	}
+	if ctx.section || r.afterMarker {
+		return FragmentQuotedReply
+	}
+	return -1
+}
+
+func (r *htmlTextRenderer) writeText(n *htmlNode, ctx htmlContext) {
+	if r.pre > 0 {
+		for i, part := range strings.Split(strings.ReplaceAll(n.text, "\r", ""), enter) {
+			if i > 0 {
+				r.ensureLine(ctx)
+				r.endLine()
+			}
+			if part != "" {
+				r.write(n, ctx, part)
+			}
+		}
+		return
+	}
+
+	// a non-breaking space is content, browsers render <div>&nbsp;</div> as an empty line
+	words := strings.FieldsFunc(n.text, isHTMLSpace)
+	if len(words) == 0 {
+		if n.text != "" {
+			r.pendingSpace = true
+		}
+		return
+	}
+	if strings.TrimLeft(n.text, " \t\r\n\f") != n.text {
+		r.pendingSpace = true
+	}
+	r.write(n, ctx, strings.Join(words, space))
+	if strings.TrimRight(n.text, " \t\r\n\f") != n.text {
+		r.pendingSpace = true
+	}
+}
+
+func isHTMLSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '\f'
+}
+
+func (r *htmlTextRenderer) write(n *htmlNode, ctx htmlContext, text string) {
+	r.ensureLine(ctx)
+	last := len(r.lines) - 1
+	if r.pendingSpace && r.lines[last] != "" {
+		r.lines[last] += space
+	}
+	r.pendingSpace = false
+	r.lines[last] += text
+	if n.firstLine == -1 {
+		n.firstLine = last
+	}
+	n.lastLine = last
+}
+
+func (r *htmlTextRenderer) ensureLine(ctx htmlContext) {
+	if r.open {
+		return
+	}
+	r.open = true
+	r.pendingSpace = false
+	r.lines = append(r.lines, "")
+	r.depths = append(r.depths, r.quoteDepth)
+	r.kinds = append(r.kinds, r.kindOf(ctx))
+}
+
+func (r *htmlTextRenderer) endLine() {
+	r.open = false
+	r.pendingSpace = false
+}
+
+// text returns the rendered lines with a > for every blockquote they are in
+func (r *htmlTextRenderer) text() []string {
+	text := make([]string, len(r.lines))
+	for i, line := range r.lines {
+		text[i] = strings.TrimRight(strings.Repeat("> ", r.depths[i])+line, space)
+	}
+	return text
+}
+
+// splitRenderedLines puts quote headers which start in the middle of a line on their own line like
+// plainMailToLines does, lineStarts contains for every rendered line the index of its first line after splitting
+func (p *Parser) splitRenderedLines(rendered []string, renderedKinds []FragmentKind) ([]string, []FragmentKind, []int) {
+	var text []string
+	var kinds []FragmentKind
+	lineStarts := make([]int, len(rendered)+1)
+	for i, line := range rendered {
+		lineStarts[i] = len(text)
+		if start := p.midLineQuoteHeaderStart(line, rendered[i+1:]); start > 0 {
+			text = append(text, strings.TrimRight(line[:start], space), line[start:])
+			kinds = append(kinds, renderedKinds[i], renderedKinds[i])
+			continue
+		}
+		text = append(text, line)
+		kinds = append(kinds, renderedKinds[i])
+	}
+	lineStarts[len(rendered)] = len(text)
+	return text, kinds, lineStarts
+}
+
+// markKeptNodes decides which nodes are part of the reply and returns if the node has text in the reply
+func (p *Parser) markKeptNodes(
+	n *htmlNode,
+	c *classification,
+	lineStarts []int,
+	keep map[*htmlNode]bool,
+) (hasText bool, hasReplyText bool) {
+	if n.tag == "" {
+		if n.firstLine == -1 {
+			keep[n] = true
+			return false, false
+		}
+		for i := lineStarts[n.firstLine]; i < lineStarts[n.lastLine+1]; i++ {
+			if isOneOfKinds(c.kind(i), p.replyKinds) {
+				keep[n] = true
+				return true, true
+			}
+		}
+		return true, false
+	}
+	if isOneOf(n.tag, skippedElements) {
+		return false, false
+	}
+	// an element which only contained a removed quote or signature is removed as well
+	if n.container != -1 && !isOneOfKinds(n.container, p.replyKinds) {
+		return true, false
+	}
+
+	for _, child := range n.children {
+		childHasText, childHasReplyText := p.markKeptNodes(child, c, lineStarts, keep)
+		hasText = hasText || childHasText
+		hasReplyText = hasReplyText || childHasReplyText
+	}
+	keep[n] = !hasText || hasReplyText
+	return hasText, hasReplyText
+}
+
+func findHTMLElement(n *htmlNode, tag string) *htmlNode {
+	if n.tag == tag {
+		return n
+	}
+	for _, child := range n.children {
+		if found := findHTMLElement(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// trimHTML removes the whitespace and line breaks which are left after the quote is removed
+func trimHTML(s string) string {
+	for {
+		trimmed := strings.TrimSuffix(strings.TrimSpace(s), "<br>")
+		if trimmed == s {
+			return s
+		}
+		s = trimmed
+	}
+}
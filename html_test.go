@@ -0,0 +1,150 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseHTML(t *testing.T) {
+	tests := []struct {
+		file string
+		html string
+		text string
+		kind []FragmentKind
+	}{
+		{
+			"gmail.html",
+			`<div dir="ltr">Sounds good, I will bring the <b>slides</b>.<div><br></div><div>See you tomorrow</div><div><br></div></div>`,
+			"Sounds good, I will bring the slides.\n\nSee you tomorrow",
+			[]FragmentKind{FragmentReply, FragmentSignature, FragmentQuoteHeader, FragmentQuotedReply},
+		},
+		{
+			"outlook.html",
+			`<div style="font-family: Calibri, Arial, Helvetica, sans-serif; font-size: 12pt;">` + "\n" +
+				`Thanks, the invoice is paid.</div>`,
+			"Thanks, the invoice is paid.",
+			[]FragmentKind{FragmentReply, FragmentQuoteHeader, FragmentQuotedReply},
+		},
+		{
+			"apple.html",
+			"Yes, that works for me.",
+			"Yes, that works for me.",
+			[]FragmentKind{FragmentReply, FragmentDeviceFooter, FragmentQuoteHeader, FragmentQuotedReply},
+		},
+		{
+			"thunderbird.html",
+			"<p>I have attached the report.</p>",
+			"I have attached the report.",
+			[]FragmentKind{FragmentReply, FragmentSignature, FragmentQuoteHeader, FragmentQuotedReply},
+		},
+	}
+	for _, test := range tests {
+		content, err := os.ReadFile("./dataset/html/" + test.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reply := ParseHTML(string(content))
+		if reply.HTML != test.html {
+			t.Errorf("%v: expected: `%v` but is `%v`", test.file, test.html, reply.HTML)
+		}
+		if reply.Text != test.text {
+			t.Errorf("%v: expected: `%v` but is `%v`", test.file, test.text, reply.Text)
+		}
+		assertFragmentKinds(t, reply.Email, test.kind)
+	}
+}
+
+func TestParseHTMLAttribution(t *testing.T) {
+	content, err := os.ReadFile("./dataset/html/gmail.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := ParseHTML(string(content)).Email
+	header := email.Fragments[2]
+	if header.Attribution == nil || header.Attribution.Name != "Karen Green" {
+		t.Fatalf("expected the quote to be written by Karen Green but is `%v`", header.Attribution)
+	}
+	if header.Attribution.Address.Address != "karen@webby.com" {
+		t.Errorf("expected: `%v` but is `%v`", "karen@webby.com", header.Attribution.Address.Address)
+	}
+}
+
+func TestParseHTMLKeepSignature(t *testing.T) {
+	content, err := os.ReadFile("./dataset/html/gmail.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply := NewParser(Options{KeepSignature: true}).ParseHTML(string(content))
+	if !strings.Contains(reply.HTML, "gmail_signature") || !strings.Contains(reply.Text, "John Smith") {
+		t.Errorf("expected the signature in `%v` and `%v`", reply.HTML, reply.Text)
+	}
+	if strings.Contains(reply.HTML, "gmail_quote") {
+		t.Errorf("expected no quote in `%v`", reply.HTML)
+	}
+}
+
+func TestParseHTMLWithoutContainers(t *testing.T) {
+	reply := ParseHTML(`<p>Hi &amp; thanks</p><script>alert("x")</script><p>Best regards,<br>Karen</p>` +
+		`<p>On Mon, Aug 26, 2019 at 4:37 PM John &lt;john@example.org&gt; wrote:</p><blockquote><p>Hello</p></blockquote>`)
+	if reply.Text != "Hi & thanks" {
+		t.Errorf("expected: `%v` but is `%v`", "Hi & thanks", reply.Text)
+	}
+	if reply.HTML != "<p>Hi &amp; thanks</p>" {
+		t.Errorf("expected: `%v` but is `%v`", "<p>Hi &amp; thanks</p>", reply.HTML)
+	}
+}
+
+func TestParseHTMLTree(t *testing.T) {
+	root := parseHTMLTree(`<div class='a b' data-x=1>one<br/>two</span><img src="x.png"><!-- comment --><p>three`)
+	var b strings.Builder
+	renderHTML(&b, root, func(n *htmlNode) bool { return false })
+	expected := `<div class="a b" data-x="1">one<br>two<img src="x.png"><p>three</p></div>`
+	if b.String() != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, b.String())
+	}
+	div := root.children[0]
+	if !div.hasClass("b") || div.attr("data-x") != "1" {
+		t.Errorf("expected class b and data-x 1 but is `%v`", div.attrs)
+	}
+}
+
+func TestParseHTMLRawTextWithMultiByteLowerCase(t *testing.T) {
+	// Ⱥ is 2 bytes but lower cased 3 bytes
+	reply := ParseHTML("<p>Hi</p><style>" + strings.Repeat("Ⱥ", 100) + "</STYLE><p>there</p>")
+	if reply.Text != "Hi\nthere" {
+		t.Errorf("expected: `%v` but is `%v`", "Hi\nthere", reply.Text)
+	}
+}
+
+func TestParseHTMLRemovesScripts(t *testing.T) {
+	reply := ParseHTML(`<p onclick="steal()">Hi <a href="javascript:alert(1)">there</a>` +
+		`<a href=" JaVa&#x09;script:alert(1)">one</a><a HREF="https://example.com" onMouseOver="x()">two</a></p>` +
+		`<img src=x onerror=alert(1)><img src="data:image/png;base64,AAAA"><a href="data:text/html,<script>">three</a>` +
+		`<iframe src="https://evil.example"></iframe><script>alert(1)</script><form action="vbscript:x"><p>four</p></form>`)
+	expected := `<p>Hi <a>there</a><a>one</a><a href="https://example.com">two</a></p>` +
+		`<img src="x"><img src="data:image/png;base64,AAAA"><a>three</a><p>four</p>`
+	if reply.HTML != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, reply.HTML)
+	}
+}
+
+func TestParseHTMLAllowsOnlyKnownElementsAndAttributes(t *testing.T) {
+	tests := map[string]string{
+		"<p>hi <img\fsrc=x\fonerror=alert(1)></p>":                                             `<p>hi <img src="x"></p>`,
+		"<p x\fonclick=alert(1)>hi</p>":                                                        `<p>hi</p>`,
+		"<p>hi</p><svg><a><animate attributeName=href values=javascript:alert(1) /></a></svg>": `<p>hi</p>`,
+		"<p>hi</p><math><a href=x>link</a></math><set attributeName=href to=javascript:x />":   `<p>hi</p>`,
+		`<p style="background:url(javascript:alert(1))">hi</p>`:                                `<p>hi</p>`,
+		`<p style="width: expression(alert(1))">hi</p>`:                                        `<p>hi</p>`,
+		`<p style="b\61ckground: u\72l(x)">hi</p>`:                                             `<p>hi</p>`,
+		`<p style="color: red; font-weight: bold">hi</p>`:                                      `<p style="color: red; font-weight: bold">hi</p>`,
+		"<p><o:p>hi</o:p> <blink>there</blink></p>":                                            `<p>hi there</p>`,
+		"<p><x\u00e9>hi</x\u00e9></p>":                                                         `<p>hi</p>`,
+	}
+	for input, expected := range tests {
+		if reply := ParseHTML(input); reply.HTML != expected {
+			t.Errorf("%q: expected: `%v` but is `%v`", input, expected, reply.HTML)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"html"
+	"strings"
+)
+
+// htmlNode is an element or a text node of a HTML email, text nodes have no tag
+type htmlNode struct {
+	tag      string
+	attrs    []htmlAttr
+	text     string
+	children []*htmlNode
+
+	// container is the kind the element gives its content or -1 when it gives none
+	container FragmentKind
+	// firstLine and lastLine are the text lines a text node was rendered to or -1
+	firstLine int
+	lastLine  int
+}
+
+type htmlAttr struct {
+	name  string
+	value string
+}
+
+// htmlSpaces are the characters browsers see as whitespace between tag names and attributes
+const htmlSpaces = " \t\n\f\r"
+
+//nolint:gochecknoglobals
+var (
+	voidElements    = []string{"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "param", "source", "track", "wbr"}
+	rawTextElements = []string{"script", "style", "textarea", "title"}
+	// allowedElements are written to the cleaned HTML, other elements are left out and only their content is written
+	allowedElements = []string{
+		"a", "abbr", "address", "article", "aside", "b", "bdi", "bdo", "big", "blockquote", "br", "caption",
+		"center", "cite", "code", "col", "colgroup", "dd", "del", "dfn", "div", "dl", "dt", "em", "figcaption",
+		"figure", "font", "footer", "h1", "h2", "h3", "h4", "h5", "h6", "header", "hr", "i", "img", "ins",
+		"kbd", "li", "main", "mark", "nav", "ol", "p", "pre", "q", "s", "samp", "section", "small", "span",
+		"strike", "strong", "sub", "sup", "table", "tbody", "td", "tfoot", "th", "thead", "time", "tr", "tt",
+		"u", "ul", "var", "wbr",
+	}
+	// allowedAttributes are written to the cleaned HTML, event handlers like onerror are not in it
+	allowedAttributes = []string{
+		"align", "alt", "bgcolor", "border", "cellpadding", "cellspacing", "cite", "class", "color", "colspan",
+		"datetime", "dir", "face", "height", "href", "id", "lang", "rowspan", "size", "span", "src", "start",
+		"style", "title", "type", "valign", "width",
+	}
+	// urlAttributes contain a link which can run a script like javascript:alert(1)
+	urlAttributes = []string{"cite", "href", "src"}
+	// unsafeStyles are the parts of a style which can load a link or run a script
+	unsafeStyles = []string{"url(", "image(", "image-set(", "expression(", "javascript:", "vbscript:", "behavior", "binding", "@import"}
+)
+
+func newHTMLNode(tag string) *htmlNode {
+	return &htmlNode{tag: tag, container: -1, firstLine: -1, lastLine: -1}
+}
+
+// attr returns the value of an attribute or an empty string
+func (n *htmlNode) attr(name string) string {
+	for _, attr := range n.attrs {
+		if attr.name == name {
+			return attr.value
+		}
+	}
+	return ""
+}
+
+// hasClass tells if the class attribute contains the class
+func (n *htmlNode) hasClass(class string) bool {
+	for _, c := range strings.Fields(n.attr("class")) {
+		if strings.EqualFold(c, class) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHTMLTree builds a tree of the HTML, it is forgiving like browsers are for the
+// broken HTML mail programs send: unknown end tags are ignored and open elements are closed at the end
+func parseHTMLTree(source string) *htmlNode {
+	root := newHTMLNode("#root")
+	stack := []*htmlNode{root}
+	appendChild := func(n *htmlNode) {
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, n)
+	}
+
+	i := 0
+	for i < len(source) {
+		if source[i] != '<' {
+			end := strings.IndexByte(source[i:], '<')
+			if end == -1 {
+				end = len(source) - i
+			}
+			text := newHTMLNode("")
+			text.text = html.UnescapeString(source[i : i+end])
+			appendChild(text)
+			i += end
+			continue
+		}
+
+		rest := source[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			i += skipPast(rest, "-->")
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			i += skipPast(rest, ">")
+		case strings.HasPrefix(rest, "</"):
+			name, _ := readTagName(rest[2:])
+			i += skipPast(rest, ">")
+			for j := len(stack) - 1; j > 0; j-- {
+				if stack[j].tag == name {
+					stack = stack[:j]
+					break
+				}
+			}
+		case len(rest) > 1 && isASCIILetter(rest[1]):
+			n, used, selfClosing := readStartTag(rest)
+			i += used
+			appendChild(n)
+			if isOneOf(n.tag, rawTextElements) {
+				end := indexOfCloseTag(source[i:], n.tag)
+				text := newHTMLNode("")
+				text.text = source[i : i+end]
+				n.children = append(n.children, text)
+				i += end
+				continue
+			}
+			if !selfClosing && !isOneOf(n.tag, voidElements) {
+				stack = append(stack, n)
+			}
+		default:
+			text := newHTMLNode("")
+			text.text = "<"
+			appendChild(text)
+			i++
+		}
+	}
+	return root
+}
+
+// skipPast returns the length of s till after the end marker or the length of s when it is missing
+func skipPast(s string, end string) int {
+	i := strings.Index(s, end)
+	if i == -1 {
+		return len(s)
+	}
+	return i + len(end)
+}
+
+// indexOfCloseTag returns the index of the close tag like </style> in any case or the length of s
+// when it is missing, tag names are ascii so the bytes are compared without lower casing s
+func indexOfCloseTag(s string, tag string) int {
+	for i := 0; ; {
+		j := strings.Index(s[i:], "</")
+		if j == -1 {
+			return len(s)
+		}
+		i += j
+		if end := i + 2 + len(tag); end <= len(s) && equalFoldASCII(s[i+2:end], tag) {
+			return i
+		}
+		i += 2
+	}
+}
+
+// equalFoldASCII compares the ascii letters of a and b without case, other bytes have to be equal
+func equalFoldASCII(a string, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if lowerASCII(a[i]) != lowerASCII(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func lowerASCII(b byte) byte {
+	if b >= 'A' && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// readTagName returns the lower case tag name at the start of s and its length
+func readTagName(s string) (string, int) {
+	i := 0
+	for i < len(s) && !isOneOfBytes(s[i], htmlSpaces+"/>") {
+		i++
+	}
+	return strings.ToLower(s[:i]), i
+}
+
+// readStartTag reads a tag like <div class="gmail_quote"> and returns the amount of bytes it used
+func readStartTag(s string) (*htmlNode, int, bool) {
+	name, i := readTagName(s[1:])
+	n := newHTMLNode(name)
+	i++
+	for i < len(s) {
+		for i < len(s) && isOneOfBytes(s[i], htmlSpaces) {
+			i++
+		}
+		switch {
+		case i >= len(s):
+			return n, i, false
+		case s[i] == '>':
+			return n, i + 1, false
+		case strings.HasPrefix(s[i:], "/>"):
+			return n, i + 2, true
+		case s[i] == '/':
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(s) && !isOneOfBytes(s[i], htmlSpaces+"=/>") {
+			i++
+		}
+		attr := htmlAttr{name: strings.ToLower(s[start:i])}
+		for i < len(s) && isOneOfBytes(s[i], htmlSpaces) {
+			i++
+		}
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isOneOfBytes(s[i], htmlSpaces) {
+				i++
+			}
+			var value string
+			value, i = readAttrValue(s, i)
+			attr.value = html.UnescapeString(value)
+		}
+		n.attrs = append(n.attrs, attr)
+	}
+	return n, i, false
+}
+
+// readAttrValue reads a quoted or unquoted attribute value starting at i
+func readAttrValue(s string, i int) (string, int) {
+	if i >= len(s) {
+		return "", i
+	}
+	if quote := s[i]; quote == '"' || quote == '\'' {
+		end := strings.IndexByte(s[i+1:], quote)
+		if end == -1 {
+			return s[i+1:], len(s)
+		}
+		return s[i+1 : i+1+end], i + end + 2
+	}
+	start := i
+	for i < len(s) && !isOneOfBytes(s[i], htmlSpaces+">") {
+		i++
+	}
+	return s[start:i], i
+}
+
+func isOneOfBytes(b byte, bytes string) bool {
+	return strings.IndexByte(bytes, b) != -1
+}
+
+// renderHTML writes the node and its children back as HTML without the nodes which are skipped
+func renderHTML(b *strings.Builder, n *htmlNode, skip func(n *htmlNode) bool) {
+	if skip(n) {
+		return
+	}
+	if n.tag == "" {
+		b.WriteString(html.EscapeString(n.text))
+		return
+	}
+	allowed := isAllowedHTMLElement(n.tag)
+	if allowed {
+		b.WriteString("<" + n.tag)
+		for _, attr := range n.attrs {
+			if !isSafeHTMLAttr(n.tag, attr) {
+				continue
+			}
+			b.WriteString(" " + attr.name + `="` + html.EscapeString(attr.value) + `"`)
+		}
+		b.WriteString(">")
+		if isOneOf(n.tag, voidElements) {
+			return
+		}
+	}
+	for _, child := range n.children {
+		renderHTML(b, child, skip)
+	}
+	if allowed {
+		b.WriteString("</" + n.tag + ">")
+	}
+}
+
+// isAllowedHTMLElement tells if the element is written to the cleaned HTML
+func isAllowedHTMLElement(tag string) bool {
+	return isHTMLName(tag) && isOneOf(tag, allowedElements)
+}
+
+// isHTMLName tells if a tag or attribute name only contains lower case letters, digits, colons and hyphens,
+// the parser does not split on every character browsers split on so other names are not written
+func isHTMLName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != ':' && c != '-' {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// isSafeHTMLAttr tells if the attribute can not run a script, only known attributes and data- attributes are allowed,
+// links like javascript: and styles with links are dropped, data: is only allowed for images
+func isSafeHTMLAttr(tag string, attr htmlAttr) bool {
+	if !isHTMLName(attr.name) || !isOneOf(attr.name, allowedAttributes) && !strings.HasPrefix(attr.name, "data-") {
+		return false
+	}
+	if attr.name == "style" {
+		return isSafeStyle(attr.value)
+	}
+	if !isOneOf(attr.name, urlAttributes) {
+		return true
+	}
+	switch scheme, rest := urlSchemeOf(attr.value); scheme {
+	case "javascript", "vbscript":
+		return false
+	case "data":
+		return tag == "img" && attr.name == "src" && strings.HasPrefix(rest, "image/")
+	}
+	return true
+}
+
+// urlSchemeOf returns the lower case scheme of a link and the rest after the colon, browsers ignore
+// whitespace and control characters in the scheme so java\tscript: is javascript: as well
+func urlSchemeOf(link string) (string, string) {
+	var scheme strings.Builder
+	for i := 0; i < len(link); i++ {
+		c := link[i]
+		switch {
+		case c <= ' ':
+			continue
+		case c == ':':
+			return scheme.String(), strings.ToLower(strings.TrimSpace(link[i+1:]))
+		case isASCIILetter(c) || (c >= '0' && c <= '9') || c == '+' || c == '-' || c == '.':
+			scheme.WriteByte(lowerASCII(c))
+		default:
+			return "", link
+		}
+	}
+	return "", link
+}
+
+// isSafeStyle tells if a style can not load a link or run a script like background:url(javascript:alert(1)),
+// css escapes and comments can hide these so styles with them are not safe either
+func isSafeStyle(style string) bool {
+	var b strings.Builder
+	for i := 0; i < len(style); i++ {
+		if style[i] > ' ' {
+			b.WriteByte(lowerASCII(style[i]))
+		}
+	}
+	compact := b.String()
+	if strings.ContainsAny(compact, "\\<>") || strings.Contains(compact, "/*") {
+		return false
+	}
+	for _, unsafe := range unsafeStyles {
+		if strings.Contains(compact, unsafe) {
+			return false
+		}
+	}
+	return true
+}
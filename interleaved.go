@@ -0,0 +1,148 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+)
+
+// QuoteAnswer is an answer which was written below a quoted part of an interleaved reply
+type QuoteAnswer struct {
+	// Quote is the quoted text the answer is about with one level of > removed
+	Quote  string
+	Answer string
+}
+
+// isInterleaved detects replies which answer below the quoted parts e.g.
+// On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+// > Can you review the PR?
+//
+// Yes, will do today.
+//
+// > Is the deploy done?
+//
+// Not yet.
+//
+// the quoted parts have to start below a quote header or at the top of the mail and there has to be
+// an answer between two quoted parts with an empty line before the next quoted part,
+// a quote directly below a line is a citation in the reply
+func (p *Parser) isInterleaved(lines []*Line) bool {
+	type block struct {
+		start  int
+		quoted bool
+		// afterEmptyLine is true when an empty line separates the block from the block above it
+		afterEmptyLine bool
+	}
+	var blocks []*block
+	for i, line := range lines {
+		if canceled(lines) {
+			return false
+		}
+		if line.IsEmpty {
+			continue
+		}
+		if len(blocks) > 0 && blocks[len(blocks)-1].quoted == line.IsQuoted {
+			continue
+		}
+		blocks = append(blocks, &block{start: i, quoted: line.IsQuoted, afterEmptyLine: i > 0 && lines[i-1].IsEmpty})
+	}
+
+	// quoted text in the middle of the reply is a citation and not an answered message
+	firstQuoted := 0
+	if len(blocks) > 0 && !blocks[0].quoted {
+		firstQuoted = 1
+		if len(blocks) < 2 || !p.isQuoteHeaderAbove(blocks[1].start, lines) {
+			return false
+		}
+	}
+
+	for i := firstQuoted + 1; i+1 < len(blocks); i++ {
+		answer := blocks[i]
+		if answer.quoted || !blocks[i+1].afterEmptyLine || p.containsQuoteStart(answer.start, blocks[i+1].start, lines) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// isQuoteHeaderAbove tells if the paragraph above the line ends with a quote header
+func (p *Parser) isQuoteHeaderAbove(lineIndex int, lines []*Line) bool {
+	above := lineIndex - 1
+	for above >= 0 && lines[above].IsEmpty {
+		above--
+	}
+	for i := above; i >= 0 && above-i < maxQuoteHeaderLines && !lines[i].IsEmpty; i-- {
+		if isQuoteStart, _ := p.detectQuotedEmailStart(i, lines[i], lines); isQuoteStart {
+			return true
+		}
+	}
+	return false
+}
+
+// containsQuoteStart tells if one of the lines from start till end starts a quoted email
+func (p *Parser) containsQuoteStart(start int, end int, lines []*Line) bool {
+	for i := start; i < end; i++ {
+		if lines[i].IsEmpty {
+			continue
+		}
+		if isQuoteStart, _ := p.detectQuotedEmailStart(i, lines[i], lines); isQuoteStart {
+			return true
+		}
+	}
+	return false
+}
+
+// classifyInterleaved classifies the text above the first quoted line like a normal reply
+// and every line which is not quoted after it as an answer till the signature
+func (p *Parser) classifyInterleaved(lines []*Line, c *classification) {
+	first := 0
+	for first < len(lines) && !lines[first].IsQuoted {
+		first++
+	}
+	p.classifyLinesWithQuotedReplyOnBottom(lines[:first], c)
+
+	for i := first; i < len(lines) && !canceled(lines); i++ {
+		line := lines[i]
+		switch {
+		case line.IsQuoted:
+			c.set(i, FragmentQuotedReply, RuleQuoted)
+		case line.IsEmpty:
+			// empty lines belong to the part above them
+			c.set(i, c.kind(i-1), c.explanations[i-1].Rule)
+		default:
+			if isQuoteStart, _ := p.detectQuotedEmailStart(i, line, lines); isQuoteStart {
+				c.set(i, FragmentQuoteHeader, RuleQuoteHeader)
+				continue
+			}
+			if rule := p.signatureStartRule(i, line, lines, c); rule != "" {
+				i = p.classifySignature(i, lines, c, rule) - 1
+				continue
+			}
+			c.set(i, FragmentReply, RuleInterleavedAnswer)
+		}
+	}
+}
+
+// quoteAnswers returns the answers of an interleaved reply with the quoted part above them
+func (p *Parser) quoteAnswers(fragments []*Fragment) []*QuoteAnswer {
+	var answers []*QuoteAnswer
+	var quote []string
+	for _, fragment := range fragments {
+		switch fragment.Kind {
+		case FragmentQuotedReply:
+			for _, line := range fragment.Lines {
+				quote = append(quote, unquoteLine(line.Content, p.quotePrefixes))
+			}
+		case FragmentReply:
+			if len(quote) > 0 {
+				answers = append(answers, &QuoteAnswer{
+					Quote:  removeWhiteSpaceBeforeAndAfter(strings.Join(quote, enter)),
+					Answer: fragment.Content,
+				})
+			}
+			quote = nil
+		default:
+			quote = nil
+		}
+	}
+	return answers
+}
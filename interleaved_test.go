@@ -0,0 +1,100 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+)
+
+const interleavedMail = `Hi John, answers inline.
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> Can you review the PR?
+
+Yes, will do today.
+
+> Is the deploy done?
+> It was planned for monday.
+
+Not yet, it moves to tuesday.
+
+> Thanks,
+> John
+
+--
+Karen Green
+`
+
+func TestInterleavedAnswers(t *testing.T) {
+	email := ParseEmail(interleavedMail)
+	if email.Layout != LayoutInterleaved {
+		t.Fatalf("expected the mail to be interleaved")
+	}
+
+	expected := []*QuoteAnswer{
+		{Quote: "Can you review the PR?", Answer: "Yes, will do today."},
+		{Quote: "Is the deploy done?\nIt was planned for monday.", Answer: "Not yet, it moves to tuesday."},
+	}
+	if len(email.Answers) != len(expected) {
+		t.Fatalf("expected: `%v` answers but is `%v`", len(expected), len(email.Answers))
+	}
+	for i, answer := range email.Answers {
+		if *answer != *expected[i] {
+			t.Errorf("expected: `%v` but is `%v`", *expected[i], *answer)
+		}
+	}
+
+	reply := "Hi John, answers inline.\n\nYes, will do today.\n\nNot yet, it moves to tuesday."
+	if email.Reply() != reply {
+		t.Errorf("expected: `%v` but is `%v`", reply, email.Reply())
+	}
+	assertFragmentKinds(t, email, []FragmentKind{
+		FragmentReply, FragmentQuoteHeader, FragmentQuotedReply, FragmentReply,
+		FragmentQuotedReply, FragmentReply, FragmentQuotedReply, FragmentSignature,
+	})
+}
+
+func TestInterleavedWithoutIntro(t *testing.T) {
+	mail := `> Can you review the PR?
+
+Yes.
+
+> Is the deploy done?
+
+No.`
+	email := ParseEmail(mail)
+	if email.Layout != LayoutInterleaved || len(email.Answers) != 2 {
+		t.Fatalf("expected two answers but is `%v` `%v`", email.Layout, len(email.Answers))
+	}
+	if email.Reply() != "Yes.\n\nNo." {
+		t.Errorf("expected: `%v` but is `%v`", "Yes.\n\nNo.", email.Reply())
+	}
+}
+
+func TestNotInterleaved(t *testing.T) {
+	tests := map[string]string{
+		// a citation in the reply without quote header above it
+		"citation": `Yes, the deploy is done.
+
+> Did you check the logs?
+
+The logs are clean.
+
+> Did you check the logs?`,
+		// a quote directly below a line belongs to that paragraph
+		"inline quote": `On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> Can you review the PR?
+
+See what you wrote before:
+> Is the deploy done?
+
+Thanks`,
+		"bottom": `Thanks!
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> Can you review the PR?`,
+	}
+	for name, mail := range tests {
+		if email := ParseEmail(mail); email.Layout == LayoutInterleaved {
+			t.Errorf("%v: expected the mail not to be interleaved", name)
+		}
+	}
+}
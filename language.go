@@ -1,97 +1,58 @@
 package email_reply_parser //nolint:stylecheck,golint
 
-var greetings = []string{
-	// English
-	`yours sincerely`,
-	`yours faithfully`,
-	`yours truly`,
-	`regards`,
-	`best regards`,
-	`with best wishes`,
-	`with many thanks and best wishes`,
-	// French
-	`meilleures salutations`,
-	`cordialement`,
-	// Polish
-	`pozdrowienia`,
-	`z poważaniem`,
-	// Dutch
-	`groeten`,
-	`vriendelijke groeten`,
-	// German
-	`freundliche Grüße`,
-	`grüße`,
-	// Portuguese
-	`cumprimentos`,
-	`saudações`,
-	// Norwegian
-	`med vennlig hilsen`,
-	`hilsen`,
-	// Swedish
-	`hälsningar`,
-	`vänliga hälsningar`,
-	// Danish
-	`Med venlig hilsen`,
-	`hilsen`,
-	// Vietnamese
-	`trân trọng`,
-}
+import (
+	"embed"
+	"time"
+)
 
-//nolint:gochecknoglobals
-var labels = []string{
-	// TODO: more languages
-	"bel",
-	"call",
-	"tel",
-	"email",
-	"mail",
-	"kvk",
-	"vat",
-	"btw",
-}
+//go:embed languages/*.json
+var embeddedLanguages embed.FS
 
-//nolint:gochecknoglobals
-var on = []string{
-	// English
-	`on`,
-	// French
-	`le`,
-	// Polish
-	`w dni`,
-	// Dutch
-	`op`,
-	// German
-	`am`,
-	// Portuguese
-	`em`,
-	// Norwegian
-	`på`,
-	// Swedish, Danish
-	`den`,
-	// Vietnamese
-	`vào`,
+// Language contains the words which are used in replies written in a language
+type Language struct {
+	// Code is the ISO 639-1 code of the language e.g. en or nl
+	Code string
+	Name string
+
+	// Greetings like best regards which start a signature
+	Greetings []string
+	// On and Wrote are the words of a quote header like On DATE, NAME <EMAIL> wrote:
+	On    []string
+	Wrote []string
+	// Quoting starts a Horde quote header like Quoting NAME <EMAIL>:
+	Quoting []string
+	// Sent are the words of a device footer like Sent from my iPhone
+	Sent []string
+	// Forwarded are the words of a forward marker like ---------- Forwarded message ----------
+	Forwarded []string
+	// OriginalMessage are the words of an Outlook quote marker like -----Original Message-----
+	OriginalMessage []string
+	// SignatureLabels are the labels before a value in a signature e.g. tel or email
+	SignatureLabels []string
+
+	// labels of the header lines above a forwarded or quoted message
+	FromLabels    []string
+	ToLabels      []string
+	CcLabels      []string
+	SubjectLabels []string
+	DateLabels    []string
+
+	// Months and Weekdays are the lowercase names and abbreviations used in dates
+	Months   map[string]time.Month
+	Weekdays map[string]time.Weekday
+	// MonthMarkers and WeekdayMarkers are followed by the number of the month or weekday e.g. Vietnamese tháng 11
+	MonthMarkers   []string
+	WeekdayMarkers []string
+	// DateConnectors are the words between the parts of a date e.g. Oct 1, 2012, at 11:55 PM
+	DateConnectors []string
 }
 
+// builtinLanguages are the languages in the languages directory
+//
 //nolint:gochecknoglobals
-var wrote = []string{
-	// English
-	`wrote`, `sent`,
-	// French
-	`a écrit`,
-	// Polish
-	`napisał`,
-	// Dutch
-	`schreef`, `verzond`, `geschreven`,
-	// German
-	`schrieb`,
-	// Portuguese
-	`escreve`,
-	// Norwegian, Swedish
-	`skrev`,
-	// Vietnamese
-	`đã viết`,
-}
+var builtinLanguages = mustLoadLanguages(embeddedLanguages, "languages")
 
+//nolint:gochecknoglobals
 var mailPrograms = []string{
 	"iPhone",
 	"Galaxy",
@@ -105,47 +66,6 @@ var mailPrograms = []string{
 	"Outlook.com",
 }
 
-//nolint:gochecknoglobals
-var sent = []string{
-	// English
-	`sent`,
-	// French
-	`envoyé`,
-	// Polish
-	`wysłane`,
-	// Dutch
-	`verzonden`,
-	`verstuurd`,
-	// German
-	`geschickt`,
-	// Portuguese
-	`enviei`,
-	// Norwegian, Swedish
-	`sendt`, `skickas`,
-	// Vietnamese
-	`gởi`,
-}
-
-//nolint:gochecknoglobals
-var forwarded = []string{
-	// English
-	`Forwarded`,
-	// French
-	`Transféré`,
-	// Polish
-	`Przekazane`,
-	// Dutch
-	`Doorgestuurd`,
-	// German
-	`Weitergeleitet`,
-	// Portuguese
-	`Encaminhado`, `Encaminhada`,
-	// Norwegian, Swedish
-	`Videresendt`, `Vidarebefordrad`,
-	// Vietnamese
-	`Chuyển tiếp`,
-}
-
 var extensions = []string{
 	"aaa",
 	"aarp",
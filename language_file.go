@@ -0,0 +1,255 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// LanguageError tells which file and field of a language file is not valid
+type LanguageError struct {
+	File string
+	// Field is empty when the file itself can not be read e.g. invalid JSON
+	Field string
+	Err   error
+}
+
+func (e *LanguageError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("email_reply_parser: %v: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("email_reply_parser: %v: %v: %v", e.File, e.Field, e.Err)
+}
+
+func (e *LanguageError) Unwrap() error {
+	return e.Err
+}
+
+//nolint:gochecknoglobals
+var (
+	errEmptyWord    = errors.New("word is empty")
+	errUnknownField = errors.New("unknown field")
+)
+
+// languageFile is the JSON format of a language pack,
+// months start with January and weekdays start with Monday
+type languageFile struct {
+	Code            string     `json:"code"`
+	Name            string     `json:"name"`
+	Greetings       []string   `json:"greetings"`
+	On              []string   `json:"on"`
+	Wrote           []string   `json:"wrote"`
+	Quoting         []string   `json:"quoting"`
+	Sent            []string   `json:"sent"`
+	Forwarded       []string   `json:"forwarded"`
+	OriginalMessage []string   `json:"originalMessage"`
+	SignatureLabels []string   `json:"signatureLabels"`
+	FromLabels      []string   `json:"fromLabels"`
+	ToLabels        []string   `json:"toLabels"`
+	CcLabels        []string   `json:"ccLabels"`
+	SubjectLabels   []string   `json:"subjectLabels"`
+	DateLabels      []string   `json:"dateLabels"`
+	Months          [][]string `json:"months"`
+	Weekdays        [][]string `json:"weekdays"`
+	MonthMarkers    []string   `json:"monthMarkers"`
+	WeekdayMarkers  []string   `json:"weekdayMarkers"`
+	DateConnectors  []string   `json:"dateConnectors"`
+}
+
+// LoadLanguageFile reads a language pack from a JSON file like the ones in the languages directory
+func LoadLanguageFile(filename string) (Language, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Language{}, &LanguageError{File: filename, Err: err}
+	}
+	return parseLanguageFile(filename, data)
+}
+
+// LoadLanguages reads all JSON language packs in a directory of the file system,
+// use os.DirFS to read them from disk
+func LoadLanguages(fsys fs.FS, dir string) ([]Language, error) {
+	filenames, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, &LanguageError{File: dir, Err: err}
+	}
+
+	languages := make([]Language, 0, len(filenames))
+	for _, filename := range filenames {
+		data, err := fs.ReadFile(fsys, filename)
+		if err != nil {
+			return nil, &LanguageError{File: filename, Err: err}
+		}
+		language, err := parseLanguageFile(filename, data)
+		if err != nil {
+			return nil, err
+		}
+		languages = append(languages, language)
+	}
+	return languages, nil
+}
+
+// RegisterLanguageDir registers all JSON language packs in the directory,
+// a pack with the code of a registered language replaces that language
+func RegisterLanguageDir(dir string) error {
+	languages, err := LoadLanguages(os.DirFS(dir), ".")
+	if err != nil {
+		return err
+	}
+	for _, language := range languages {
+		if err := RegisterLanguage(language); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mustLoadLanguages(fsys fs.FS, dir string) []Language {
+	languages, err := LoadLanguages(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return languages
+}
+
+func parseLanguageFile(filename string, data []byte) (Language, error) {
+	var file languageFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return Language{}, jsonLanguageError(filename, err)
+	}
+
+	if strings.TrimSpace(file.Code) == "" {
+		return Language{}, &LanguageError{File: filename, Field: "code", Err: ErrMissingLanguageCode}
+	}
+
+	lists := []struct {
+		field string
+		words []string
+	}{
+		{"greetings", file.Greetings},
+		{"on", file.On},
+		{"wrote", file.Wrote},
+		{"quoting", file.Quoting},
+		{"sent", file.Sent},
+		{"forwarded", file.Forwarded},
+		{"originalMessage", file.OriginalMessage},
+		{"signatureLabels", file.SignatureLabels},
+		{"fromLabels", file.FromLabels},
+		{"toLabels", file.ToLabels},
+		{"ccLabels", file.CcLabels},
+		{"subjectLabels", file.SubjectLabels},
+		{"dateLabels", file.DateLabels},
+		{"monthMarkers", file.MonthMarkers},
+		{"weekdayMarkers", file.WeekdayMarkers},
+		{"dateConnectors", file.DateConnectors},
+	}
+	for _, list := range lists {
+		if err := validateWords(filename, list.field, list.words); err != nil {
+			return Language{}, err
+		}
+	}
+
+	months, err := namesByIndex(filename, "months", file.Months, 12)
+	if err != nil {
+		return Language{}, err
+	}
+	weekdays, err := namesByIndex(filename, "weekdays", file.Weekdays, 7)
+	if err != nil {
+		return Language{}, err
+	}
+
+	language := Language{
+		Code:            strings.ToLower(strings.TrimSpace(file.Code)),
+		Name:            file.Name,
+		Greetings:       file.Greetings,
+		On:              file.On,
+		Wrote:           file.Wrote,
+		Quoting:         file.Quoting,
+		Sent:            file.Sent,
+		Forwarded:       file.Forwarded,
+		OriginalMessage: file.OriginalMessage,
+		SignatureLabels: file.SignatureLabels,
+		FromLabels:      file.FromLabels,
+		ToLabels:        file.ToLabels,
+		CcLabels:        file.CcLabels,
+		SubjectLabels:   file.SubjectLabels,
+		DateLabels:      file.DateLabels,
+		MonthMarkers:    file.MonthMarkers,
+		WeekdayMarkers:  file.WeekdayMarkers,
+		DateConnectors:  file.DateConnectors,
+		Months:          map[string]time.Month{},
+		Weekdays:        map[string]time.Weekday{},
+	}
+	for name, i := range months {
+		language.Months[name] = time.Month(i + 1)
+	}
+	for name, i := range weekdays {
+		// the file starts with Monday and time.Weekday with Sunday
+		language.Weekdays[name] = time.Weekday((i + 1) % 7)
+	}
+	return language, nil
+}
+
+func jsonLanguageError(filename string, err error) error {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return &LanguageError{File: filename, Field: typeError.Field, Err: err}
+	}
+
+	// json returns unknown fields as json: unknown field "name"
+	const unknownFieldPrefix = "json: unknown field "
+	if strings.HasPrefix(err.Error(), unknownFieldPrefix) {
+		field := strings.Trim(strings.TrimPrefix(err.Error(), unknownFieldPrefix), `"`)
+		return &LanguageError{File: filename, Field: field, Err: errUnknownField}
+	}
+	return &LanguageError{File: filename, Err: err}
+}
+
+func validateWords(filename string, field string, words []string) error {
+	for i, word := range words {
+		if strings.TrimSpace(word) == "" {
+			return &LanguageError{File: filename, Field: fmt.Sprintf("%v[%v]", field, i), Err: errEmptyWord}
+		}
+	}
+	return nil
+}
+
+// namesByIndex returns the index of every name in a list like the months of the year
+func namesByIndex(filename string, field string, names [][]string, expected int) (map[string]int, error) {
+	byIndex := map[string]int{}
+	if len(names) == 0 {
+		return byIndex, nil
+	}
+	if len(names) != expected {
+		return nil, &LanguageError{
+			File:  filename,
+			Field: field,
+			Err:   fmt.Errorf("expected %v entries but got %v", expected, len(names)),
+		}
+	}
+	for i, list := range names {
+		for j, name := range list {
+			entryField := fmt.Sprintf("%v[%v][%v]", field, i, j)
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				return nil, &LanguageError{File: filename, Field: entryField, Err: errEmptyWord}
+			}
+			if other, ok := byIndex[name]; ok && other != i {
+				return nil, &LanguageError{
+					File:  filename,
+					Field: entryField,
+					Err:   fmt.Errorf("%v is already used in %v[%v]", name, field, other),
+				}
+			}
+			byIndex[name] = i
+		}
+	}
+	return byIndex, nil
+}
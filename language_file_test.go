@@ -0,0 +1,88 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestBuiltinLanguages(t *testing.T) {
+	codes := map[string]bool{}
+	for _, language := range builtinLanguages {
+		codes[language.Code] = true
+	}
+	for _, code := range []string{"en", "fr", "pl", "nl", "de", "pt", "no", "sv", "da", "vi"} {
+		if !codes[code] {
+			t.Errorf("Should be a builtin language: %v", code)
+		}
+	}
+
+	dutch := languagesByCode([]string{"nl"})[0]
+	if dutch.Months["mei"] != time.May {
+		t.Errorf("expected: `%v` but is `%v`", time.May, dutch.Months["mei"])
+	}
+	if dutch.Weekdays["za"] != time.Saturday {
+		t.Errorf("expected: `%v` but is `%v`", time.Saturday, dutch.Weekdays["za"])
+	}
+}
+
+func TestLanguageFileErrors(t *testing.T) {
+	tests := []struct {
+		content string
+		field   string
+	}{
+		{content: `{"code": "xx", "greetings": ["hi", " "]}`, field: "greetings[1]"},
+		{content: `{"name": "No code"}`, field: "code"},
+		{content: `{"code": "xx", "greeting": ["hi"]}`, field: "greeting"},
+		{content: `{"code": "xx", "wrote": "wrote"}`, field: "wrote"},
+		{content: `{"code": "xx", "months": [["jan"]]}`, field: "months"},
+		{content: `{"code": "xx", "weekdays": [["mo"], ["mo"], [], [], [], [], []]}`, field: "weekdays[1][0]"},
+		{content: `{"code": "xx",`, field: ""},
+	}
+	for _, test := range tests {
+		fsys := fstest.MapFS{"packs/xx.json": {Data: []byte(test.content)}}
+		_, err := LoadLanguages(fsys, "packs")
+
+		var languageErr *LanguageError
+		if !errors.As(err, &languageErr) {
+			t.Errorf("Should return a LanguageError for %v but is %v", test.content, err)
+			continue
+		}
+		if languageErr.File != "packs/xx.json" {
+			t.Errorf("expected file: `%v` but is `%v`", "packs/xx.json", languageErr.File)
+		}
+		if languageErr.Field != test.field {
+			t.Errorf("expected field: `%v` but is `%v` (%v)", test.field, languageErr.Field, err)
+		}
+	}
+}
+
+func TestRegisterLanguageDir(t *testing.T) {
+	dir := t.TempDir()
+	afrikaans := `{
+  "code": "af",
+  "name": "Afrikaans",
+  "greetings": ["groete", "vriendelike groete"],
+  "on": ["op"],
+  "wrote": ["het geskryf"]
+}`
+	if err := os.WriteFile(filepath.Join(dir, "af.json"), []byte(afrikaans), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterLanguageDir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	parser := NewParser(Options{Languages: []string{"af"}})
+	content := parser.Parse("Dankie vir die boodskap\n\nVriendelike groete,\nrl")
+	if content != "Dankie vir die boodskap" {
+		t.Errorf("expected: `%v` but is `%v`", "Dankie vir die boodskap", content)
+	}
+
+	if _, err := LoadLanguageFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("Should return an error for a missing file")
+	}
+}
@@ -0,0 +1,179 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMissingLanguageCode is returned when a language without code is registered
+var ErrMissingLanguageCode = errors.New("email_reply_parser: language code is required")
+
+// ErrUnknownLanguage is returned by Options.Validate when a language code is not registered
+var ErrUnknownLanguage = errors.New("email_reply_parser: unknown language")
+
+//nolint:gochecknoglobals
+var (
+	languagesMutex        sync.RWMutex
+	registeredLanguages   = copyLanguages(builtinLanguages)
+	defaultParserInstance *Parser
+	// languagesGeneration changes on every RegisterLanguage so a default parser which was built
+	// with the languages before it is not cached
+	languagesGeneration int
+)
+
+// RegisterLanguage adds a language or replaces the language with the same code,
+// Parse and parsers created after this call will use it
+func RegisterLanguage(language Language) error {
+	language.Code = strings.ToLower(strings.TrimSpace(language.Code))
+	if language.Code == "" {
+		return ErrMissingLanguageCode
+	}
+
+	languagesMutex.Lock()
+	defer languagesMutex.Unlock()
+
+	defaultParserInstance = nil
+	languagesGeneration++
+	for i, registered := range registeredLanguages {
+		if registered.Code == language.Code {
+			registeredLanguages[i] = language
+			return nil
+		}
+	}
+	registeredLanguages = append(registeredLanguages, language)
+	return nil
+}
+
+// Languages returns all registered languages
+func Languages() []Language {
+	languagesMutex.RLock()
+	defer languagesMutex.RUnlock()
+	return copyLanguages(registeredLanguages)
+}
+
+func copyLanguages(languages []Language) []Language {
+	return append([]Language(nil), languages...)
+}
+
+// languagesByCode returns the registered languages with the given codes, all of them are returned
+// when no codes are given or none of the codes is registered
+func languagesByCode(codes []string) []Language {
+	all := Languages()
+	var languages []Language
+	for _, language := range all {
+		if isOneOfCodes(language.Code, codes) {
+			languages = append(languages, language)
+		}
+	}
+	if len(codes) == 0 {
+		return all
+	}
+	return languages
+}
+
+// unknownLanguageCodes returns the codes which are not registered
+func unknownLanguageCodes(codes []string) []string {
+	all := Languages()
+	var unknown []string
+	for _, code := range codes {
+		known := false
+		for _, language := range all {
+			known = known || isOneOfCodes(language.Code, []string{code})
+		}
+		if !known {
+			unknown = append(unknown, code)
+		}
+	}
+	return unknown
+}
+
+func isOneOfCodes(code string, codes []string) bool {
+	for _, c := range codes {
+		if strings.EqualFold(code, strings.TrimSpace(c)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns ErrUnknownLanguage when one of the language codes is not registered,
+// NewParser ignores those codes and only uses the languages which are known
+func (o Options) Validate() error {
+	if unknown := unknownLanguageCodes(o.Languages); len(unknown) > 0 {
+		return fmt.Errorf("%w: %v", ErrUnknownLanguage, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// defaultParser returns the parser used by the package level functions
+func defaultParser() *Parser {
+	for {
+		languagesMutex.RLock()
+		p := defaultParserInstance
+		generation := languagesGeneration
+		languagesMutex.RUnlock()
+		if p != nil {
+			return p
+		}
+
+		p = NewParser(Options{})
+		languagesMutex.Lock()
+		// a language registered while the parser was built is not used by it
+		current := generation == languagesGeneration
+		if current {
+			defaultParserInstance = p
+		}
+		languagesMutex.Unlock()
+		if current {
+			return p
+		}
+	}
+}
+
+// mergeLanguages combines the words of multiple languages in one language
+func mergeLanguages(languages []Language) Language {
+	merged := Language{
+		Months:   map[string]time.Month{},
+		Weekdays: map[string]time.Weekday{},
+	}
+	var codes []string
+	for _, language := range languages {
+		codes = append(codes, language.Code)
+		merged.Greetings = appendUnique(merged.Greetings, language.Greetings...)
+		merged.On = appendUnique(merged.On, language.On...)
+		merged.Wrote = appendUnique(merged.Wrote, language.Wrote...)
+		merged.Quoting = appendUnique(merged.Quoting, language.Quoting...)
+		merged.Sent = appendUnique(merged.Sent, language.Sent...)
+		merged.Forwarded = appendUnique(merged.Forwarded, language.Forwarded...)
+		merged.OriginalMessage = appendUnique(merged.OriginalMessage, language.OriginalMessage...)
+		merged.SignatureLabels = appendUnique(merged.SignatureLabels, language.SignatureLabels...)
+		merged.FromLabels = appendUnique(merged.FromLabels, language.FromLabels...)
+		merged.ToLabels = appendUnique(merged.ToLabels, language.ToLabels...)
+		merged.CcLabels = appendUnique(merged.CcLabels, language.CcLabels...)
+		merged.SubjectLabels = appendUnique(merged.SubjectLabels, language.SubjectLabels...)
+		merged.DateLabels = appendUnique(merged.DateLabels, language.DateLabels...)
+		merged.MonthMarkers = appendUnique(merged.MonthMarkers, language.MonthMarkers...)
+		merged.WeekdayMarkers = appendUnique(merged.WeekdayMarkers, language.WeekdayMarkers...)
+		merged.DateConnectors = appendUnique(merged.DateConnectors, language.DateConnectors...)
+		for name, month := range language.Months {
+			merged.Months[strings.ToLower(name)] = month
+		}
+		for name, weekday := range language.Weekdays {
+			merged.Weekdays[strings.ToLower(name)] = weekday
+		}
+	}
+	merged.Code = strings.Join(codes, ",")
+	return merged
+}
+
+func appendUnique(a []string, values ...string) []string {
+	for _, value := range values {
+		if !isOneOf(strings.ToLower(value), a) {
+			a = append(a, value)
+		}
+	}
+	return a
+}
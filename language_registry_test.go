@@ -0,0 +1,122 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestParserLanguages(t *testing.T) {
+	header := "Op za 8 mei 2021 om 12:09 schreef Richard Lindhout:"
+	if ParseAttribution(header) == nil {
+		t.Errorf("Should be an attribution with all languages: %v", header)
+	}
+
+	english := NewParser(Options{Languages: []string{"en"}})
+	if english.ParseAttribution(header) != nil {
+		t.Errorf("Should not be an attribution in English: %v", header)
+	}
+
+	mail := "Dat klopt\n\n" + header + "\n> Klopt dit?"
+	if content := english.Parse(mail); content != mail {
+		t.Errorf("expected: `%v` but is `%v`", mail, content)
+	}
+	if content := Parse(mail); content != "Dat klopt" {
+		t.Errorf("expected: `%v` but is `%v`", "Dat klopt", content)
+	}
+}
+
+func TestDefaultOptionsLanguages(t *testing.T) {
+	options := DefaultOptions("en")
+	if len(options.On) != 1 || options.On[0] != "on" {
+		t.Errorf("expected: `%v` but is `%v`", []string{"on"}, options.On)
+	}
+	for _, greeting := range options.Greetings {
+		if greeting == "groeten" {
+			t.Errorf("Dutch greetings should not be in the English options")
+		}
+	}
+}
+
+func TestRegisterLanguage(t *testing.T) {
+	if err := RegisterLanguage(Language{}); err != ErrMissingLanguageCode {
+		t.Errorf("expected: `%v` but is `%v`", ErrMissingLanguageCode, err)
+	}
+
+	mail := "Perfecto\n\n" +
+		"El lun, 4 nov 2013 a las 16:29, Juan escribió:\n" +
+		"> Nos vemos mañana?"
+	if content := Parse(mail); content != mail {
+		t.Errorf("Spanish is not registered yet: `%v`", content)
+	}
+
+	spanish := Language{
+		Code:      "es",
+		Name:      "Spanish",
+		Greetings: []string{`saludos`},
+		On:        []string{`el`},
+		Wrote:     []string{`escribió`},
+	}
+	if err := RegisterLanguage(spanish); err != nil {
+		t.Fatal(err)
+	}
+
+	parser := NewParser(Options{Languages: []string{"es"}})
+	if content := parser.Parse(mail); content != "Perfecto" {
+		t.Errorf("expected: `%v` but is `%v`", "Perfecto", content)
+	}
+	if content := Parse(mail); content != "Perfecto" {
+		t.Errorf("Parse should use registered languages, expected: `%v` but is `%v`", "Perfecto", content)
+	}
+
+	var registered bool
+	for _, language := range Languages() {
+		registered = registered || language.Code == "es"
+	}
+	if !registered {
+		t.Errorf("Spanish should be registered")
+	}
+}
+
+func TestUnknownLanguage(t *testing.T) {
+	options := Options{Languages: []string{"english"}}
+	if err := options.Validate(); !errors.Is(err, ErrUnknownLanguage) {
+		t.Errorf("expected: `%v` but is `%v`", ErrUnknownLanguage, err)
+	}
+	if err := (Options{Languages: []string{"en", "NL"}}).Validate(); err != nil {
+		t.Errorf("expected: `%v` but is `%v`", nil, err)
+	}
+
+	// unknown codes are ignored and never turn on the other languages
+	mail := "Thanks for the update\n\nOp za 8 mei 2021 om 12:09 schreef Richard Lindhout:\n\nDe deploy is klaar."
+	for _, languages := range [][]string{{"english"}, {"en", "english"}} {
+		if content := NewParser(Options{Languages: languages}).Parse(mail); content != mail {
+			t.Errorf("%v: expected: `%v` but is `%v`", languages, mail, content)
+		}
+	}
+	if content := NewParser(Options{Languages: []string{"nl", "english"}}).Parse(mail); content != "Thanks for the update" {
+		t.Errorf("expected: `%v` but is `%v`", "Thanks for the update", content)
+	}
+}
+
+func TestDefaultParserConcurrentRegister(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			defaultParser()
+		}()
+		go func() {
+			defer wg.Done()
+			if err := RegisterLanguage(Language{Code: "xx", Greetings: []string{"groetjes xx"}}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if content := Parse("Thanks\n\ngroetjes xx\nJohn"); content != "Thanks" {
+		t.Errorf("the default parser should use the last registered language, expected: `%v` but is `%v`", "Thanks", content)
+	}
+}
@@ -0,0 +1,133 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+)
+
+// Layout tells where the sender wrote the reply compared to the quoted text
+type Layout int
+
+const (
+	// LayoutNoQuote is an email without quoted text
+	LayoutNoQuote Layout = iota
+	// LayoutTopPosted is a reply above the quoted text
+	LayoutTopPosted
+	// LayoutBottomPosted is a reply below the quoted text
+	LayoutBottomPosted
+	// LayoutInterleaved is a reply with answers below the quoted parts
+	LayoutInterleaved
+)
+
+//nolint:gochecknoglobals
+var layoutNames = map[Layout]string{
+	LayoutNoQuote:      "no quote",
+	LayoutTopPosted:    "top posted",
+	LayoutBottomPosted: "bottom posted",
+	LayoutInterleaved:  "interleaved",
+}
+
+func (l Layout) String() string {
+	if name, ok := layoutNames[l]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// detectLayout scores the whole email to find out where the reply is written,
+// a reply is bottom posted when nothing but a quote header is above the quoted text
+// or when the quoted text starts with a quote header and there is more text below the last > line
+// than above the quote header, the signature and mailing list footer below the quoted text are not counted
+func (p *Parser) detectLayout(lines []*Line) Layout {
+	if p.isInterleaved(lines) {
+		return LayoutInterleaved
+	}
+
+	isHeader := make([]bool, len(lines))
+	firstQuote := -1
+	lastQuoted := -1
+	for i, line := range lines {
+		if canceled(lines) {
+			break
+		}
+		if !line.IsQuoted && !isHeader[i] {
+			if isQuoteStart, _ := p.detectQuotedEmailStart(i, line, lines); isQuoteStart {
+				for j := i; j < i+p.quoteHeaderLength(i, lines) && j < len(lines); j++ {
+					isHeader[j] = true
+				}
+			}
+		}
+		if firstQuote == -1 && (isHeader[i] || line.IsQuoted) {
+			firstQuote = i
+		}
+		if line.IsQuoted {
+			lastQuoted = i
+		}
+	}
+	if firstQuote == -1 {
+		return LayoutNoQuote
+	}
+
+	above := countReplyLines(lines, isHeader, 0, firstQuote)
+	if above == 0 {
+		return LayoutBottomPosted
+	}
+	// quoted text in the middle of the reply is a citation
+	if !isHeader[firstQuote] || lastQuoted == -1 {
+		return LayoutTopPosted
+	}
+
+	// an answer is separated from the quoted text by an empty line, text right below it belongs to the quote
+	start := lastQuoted + 1
+	if start < len(lines) && !lines[start].IsEmpty {
+		return LayoutTopPosted
+	}
+	end := start
+	c := newClassification(lines)
+	for end < len(lines) && !canceled(lines) && !isMailingListFooter(lines[end]) && p.signatureStartRule(end, lines[end], lines, c) == "" {
+		end++
+	}
+	if below := countReplyLines(lines, isHeader, start, end); below > above {
+		return LayoutBottomPosted
+	}
+	return LayoutTopPosted
+}
+
+// mailingListFooters are the lines a mailing list adds below every message
+//
+//nolint:gochecknoglobals
+var mailingListFooters = []string{
+	"you received this message because you are subscribed",
+	"to unsubscribe from this group",
+	"mailman/listinfo",
+}
+
+// isMailingListFooter tells if the line starts or is part of the footer a mailing list adds below a message like
+// _______________________________________________
+// riak-users mailing list
+func isMailingListFooter(line *Line) bool {
+	stripped := line.ContentStripped
+	if len(stripped) >= 8 && areStripes(stripped) {
+		return true
+	}
+	lower := foldCase(stripped)
+	if strings.HasSuffix(lower, "mailing list") && strings.Count(lower, space) <= 2 {
+		return true
+	}
+	for _, footer := range mailingListFooters {
+		if strings.Contains(lower, footer) {
+			return true
+		}
+	}
+	return false
+}
+
+// countReplyLines counts the lines from start till end which are not empty, quoted or part of a quote header
+func countReplyLines(lines []*Line, isHeader []bool, start int, end int) int {
+	var count int
+	for i := start; i < end; i++ {
+		if !lines[i].IsEmpty && !lines[i].IsQuoted && !isHeader[i] {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,112 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+)
+
+func TestDetectLayout(t *testing.T) {
+	tests := map[string]struct {
+		mail   string
+		layout Layout
+		reply  string
+	}{
+		"no quote": {
+			mail:   "Thanks for the update.\n\nKaren",
+			layout: LayoutNoQuote,
+			reply:  "Thanks for the update.\n\nKaren",
+		},
+		"top posted": {
+			mail: `Thanks for the update.
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> The deploy is done.`,
+			layout: LayoutTopPosted,
+			reply:  "Thanks for the update.",
+		},
+		"bottom posted": {
+			mail: `On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> The deploy is done.
+
+Thanks for the update.`,
+			layout: LayoutBottomPosted,
+			reply:  "Thanks for the update.",
+		},
+		"bottom posted without quote header": {
+			mail: `> The deploy is done.
+> Did you check the logs?
+
+Thanks for the update, the logs are clean.`,
+			layout: LayoutBottomPosted,
+			reply:  "Thanks for the update, the logs are clean.",
+		},
+		"bottom posted below an introduction": {
+			mail: `Hi John,
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> The deploy is done.
+> Did you check the logs?
+
+Thanks for the update.
+I checked the logs this morning.
+They are clean.
+
+--
+Karen Green
+Graphic Designer`,
+			layout: LayoutBottomPosted,
+			reply:  "Hi John,\n\nThanks for the update.\nI checked the logs this morning.\nThey are clean.",
+		},
+		"top posted with a google groups footer": {
+			mail: `Yes that works.
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> The deploy is done.
+> Did you check the logs?
+
+You received this message because you are subscribed to the Google Groups "Deploy" group.
+To unsubscribe from this group and stop receiving emails from it, send an email to deploy+unsubscribe@googlegroups.com.
+To view this discussion on the web visit https://groups.google.com/d/msgid/deploy/123%40mail.gmail.com.
+For more options, visit https://groups.google.com/d/optout.`,
+			layout: LayoutTopPosted,
+			reply:  "Yes that works.",
+		},
+		"top posted with a mailman footer": {
+			mail: `Yes that works.
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> The deploy is done.
+> Did you check the logs?
+
+_______________________________________________
+riak-users mailing list
+riak-users@lists.basho.com
+http://lists.basho.com/mailman/listinfo/riak-users_lists.basho.com`,
+			layout: LayoutTopPosted,
+			reply:  "Yes that works.",
+		},
+		"top posted with text right below the quote": {
+			mail: `Yes that works.
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> The deploy is done.
+Sent with the deploy bot, reply to this message to start a new deploy.
+Manage your notifications in the deploy settings.`,
+			layout: LayoutTopPosted,
+			reply:  "Yes that works.",
+		},
+		"interleaved": {
+			mail:   interleavedMail,
+			layout: LayoutInterleaved,
+			reply:  "Hi John, answers inline.\n\nYes, will do today.\n\nNot yet, it moves to tuesday.",
+		},
+	}
+	for name, test := range tests {
+		email := ParseEmail(test.mail)
+		if email.Layout != test.layout {
+			t.Errorf("%v: expected: `%v` but is `%v`", name, test.layout, email.Layout)
+		}
+		if email.Reply() != test.reply {
+			t.Errorf("%v: expected: `%v` but is `%v`", name, test.reply, email.Reply())
+		}
+	}
+}
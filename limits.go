@@ -0,0 +1,138 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrTooLarge is returned by ParseContext when the email has more bytes than Limits.MaxBytes
+	ErrTooLarge = errors.New("email_reply_parser: email too large")
+	// ErrTooManyLines is returned by ParseContext when the email has more lines than Limits.MaxLines
+	ErrTooManyLines = errors.New("email_reply_parser: too many lines")
+	// ErrLineTooLong is returned by ParseContext when a line has more bytes than Limits.MaxLineLength
+	ErrLineTooLong = errors.New("email_reply_parser: line too long")
+)
+
+// Limits bounds the emails ParseContext parses so an email from an untrusted source can not keep
+// a worker busy, limits which are zero are not checked
+type Limits struct {
+	// MaxBytes is the maximum size of the email in bytes
+	MaxBytes int
+	// MaxLines is the maximum amount of lines of the email
+	MaxLines int
+	// MaxLineLength is the maximum size of a line in bytes
+	MaxLineLength int
+	// Truncate parses the part of the email within the limits and sets Email.Truncated
+	// instead of returning an error
+	Truncate bool
+}
+
+// ParseContext splits the email in fragments like ParseEmail, it returns the error of the context
+// when the context is done before the email is parsed
+func ParseContext(ctx context.Context, plainMail string, limits Limits) (*Email, error) {
+	return defaultParser().ParseContext(ctx, plainMail, limits)
+}
+
+// ParseContext splits the email in fragments like ParseEmail, it returns the error of the context
+// when the context is done before the email is parsed
+func (p *Parser) ParseContext(ctx context.Context, plainMail string, limits Limits) (*Email, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	plainMail, truncated, err := limits.apply(plainMail)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := p.limitedLines(plainMail, limits.MaxLineLength, ctx.Done())
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	lines, c, forward := p.classifyLines(lines)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	email := p.emailFromClassification(lines, c, forward)
+	email.Truncated = truncated
+	return email, nil
+}
+
+// apply returns the part of the email within the limits and if it was truncated,
+// or an error when the email exceeds the limits and they do not truncate
+func (l Limits) apply(plainMail string) (string, bool, error) {
+	var truncated bool
+	if l.MaxBytes > 0 && len(plainMail) > l.MaxBytes {
+		if !l.Truncate {
+			return "", false, fmt.Errorf("%w: %v bytes, the maximum is %v", ErrTooLarge, len(plainMail), l.MaxBytes)
+		}
+		plainMail = truncateUTF8(plainMail, l.MaxBytes)
+		truncated = true
+	}
+	if l.MaxLines > 0 {
+		if end := indexOfLineEnd(plainMail, l.MaxLines); end != -1 && end+len(enter) < len(plainMail) {
+			if !l.Truncate {
+				return "", false, fmt.Errorf("%w: %v lines, the maximum is %v",
+					ErrTooManyLines, strings.Count(plainMail, enter)+1, l.MaxLines)
+			}
+			plainMail = plainMail[:end]
+			truncated = true
+		}
+	}
+	if l.MaxLineLength > 0 {
+		for number, offset := 1, 0; offset <= len(plainMail); number++ {
+			length := strings.Index(plainMail[offset:], enter)
+			if length == -1 {
+				length = len(plainMail) - offset
+			}
+			if length > l.MaxLineLength {
+				if !l.Truncate {
+					return "", false, fmt.Errorf("%w: line %v has %v bytes, the maximum is %v",
+						ErrLineTooLong, number, length, l.MaxLineLength)
+				}
+				// the lines are cut while they are split so the offsets of the lines stay the same
+				truncated = true
+				break
+			}
+			offset += length + len(enter)
+		}
+	}
+	return plainMail, truncated, nil
+}
+
+// indexOfLineEnd returns the index of the line break which ends the given line or -1
+func indexOfLineEnd(v string, line int) int {
+	offset := 0
+	for i := 0; i < line; i++ {
+		next := strings.Index(v[offset:], enter)
+		if next == -1 {
+			return -1
+		}
+		offset += next + len(enter)
+	}
+	return offset - len(enter)
+}
+
+// truncateUTF8 returns at most the first n bytes of v without cutting a character in half
+func truncateUTF8(v string, n int) string {
+	if len(v) <= n {
+		return v
+	}
+	for n > 0 && !utf8.RuneStart(v[n]) {
+		n--
+	}
+	return v[:n]
+}
+
+// isDone tells if the channel of a done context is closed, a nil channel is never closed
+func isDone(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
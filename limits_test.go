@@ -0,0 +1,128 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseContextLimits(t *testing.T) {
+	mail := "Thanks, I will look at it.\n\nOn Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\n> Did you check the logs?\n"
+	tests := []struct {
+		limits   Limits
+		expected error
+	}{
+		{Limits{}, nil},
+		{Limits{MaxBytes: len(mail), MaxLines: 4, MaxLineLength: 66}, nil},
+		{Limits{MaxBytes: 20}, ErrTooLarge},
+		{Limits{MaxLines: 3}, ErrTooManyLines},
+		{Limits{MaxLineLength: 40}, ErrLineTooLong},
+	}
+	for _, test := range tests {
+		email, err := ParseContext(context.Background(), mail, test.limits)
+		if !errors.Is(err, test.expected) {
+			t.Errorf("%+v: expected: `%v` but is `%v`", test.limits, test.expected, err)
+		}
+		if err == nil && email.Reply() != "Thanks, I will look at it." {
+			t.Errorf("%+v: expected: `%v` but is `%v`", test.limits, "Thanks, I will look at it.", email.Reply())
+		}
+		if err != nil && email != nil {
+			t.Errorf("%+v: expected no email with the error `%v`", test.limits, err)
+		}
+	}
+}
+
+func TestParseContextTruncate(t *testing.T) {
+	mail := "Thanks, I will look at it.\n\nBest regards,\nKaren\n\n" + strings.Repeat("> a very long quoted line\n", 100)
+	email, err := ParseContext(context.Background(), mail, Limits{MaxLines: 4, Truncate: true})
+	if err != nil {
+		t.Fatalf("expected: `%v` but is `%v`", nil, err)
+	}
+	if !email.Truncated || email.Reply() != "Thanks, I will look at it." {
+		t.Errorf("expected a truncated reply but is `%v` `%v`", email.Truncated, email.Reply())
+	}
+
+	email, err = ParseContext(context.Background(), "Thanks äöü\nsee you", Limits{MaxBytes: 12, Truncate: true})
+	if err != nil || email.Reply() != "Thanks äö" {
+		t.Errorf("expected: `%v` but is `%v` `%v`", "Thanks äö", email.Reply(), err)
+	}
+
+	email, err = ParseContext(context.Background(), strings.Repeat("x", 100)+"\nsee you", Limits{MaxLineLength: 10, Truncate: true})
+	if err != nil {
+		t.Fatalf("expected: `%v` but is `%v`", nil, err)
+	}
+	lines := email.Fragments[0].Lines
+	if lines[0].Content != strings.Repeat("x", 10) || lines[1].Offset != 101 {
+		t.Errorf("expected the first line to be cut but is `%v` and the second line at `%v`", lines[0].Content, lines[1].Offset)
+	}
+}
+
+func TestParseContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ParseContext(ctx, longThread(100), Limits{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected: `%v` but is `%v`", context.Canceled, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := ParseContext(ctx, longThread(10000), Limits{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected: `%v` but is `%v`", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the parse to stop at the deadline but took `%v`", elapsed)
+	}
+}
+
+func TestParseContextHostile(t *testing.T) {
+	mails := []string{
+		"Hi\n\n" + strings.Repeat("To: john@smith.org\n", 100000),
+		strings.Repeat("x On ", 50000) + ":",
+		"Hi\n\n" + strings.Repeat("On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\n\n", 20000),
+	}
+	for i, mail := range mails {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		_, err := ParseContext(ctx, mail, Limits{})
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("%v: expected: `%v` but is `%v`", i, context.DeadlineExceeded, err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("%v: expected the parse to stop at the deadline but took `%v`", i, elapsed)
+		}
+	}
+
+	// a long line is cut before the quote headers in it are searched
+	start := time.Now()
+	email, err := ParseContext(context.Background(), mails[1], Limits{MaxLineLength: 1000, Truncate: true})
+	if err != nil || !email.Truncated {
+		t.Errorf("expected a truncated email but is `%v` `%v`", email, err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected the cut line to be parsed fast but took `%v`", elapsed)
+	}
+}
+
+func TestRemoveWhitespace(t *testing.T) {
+	tests := map[string]string{
+		"  Best \t regards,  ":                  "Best regards,",
+		"a" + strings.Repeat(" ", 100000) + "b": "a b",
+		"":                                      "",
+	}
+	for v, expected := range tests {
+		if is := removeWhitespace(v); is != expected {
+			t.Errorf("expected: `%v` but is `%v`", expected, is)
+		}
+	}
+	if is := removeEnters("\nOn Mon\n\nwrote:\n"); is != "On Monwrote:" {
+		t.Errorf("expected: `%v` but is `%v`", "On Monwrote:", is)
+	}
+	if is := removeWhiteSpaceBeforeAndAfter("\n \r\n reply \n\n"); is != "reply" {
+		t.Errorf("expected: `%v` but is `%v`", "reply", is)
+	}
+}
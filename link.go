@@ -0,0 +1,181 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// linkPunctuation is trimmed from the words of a sentence before they are recognized as a link,
+// e.g. in (example.com), *www.example.com* or info@example.com,
+const linkPunctuation = "*\"'()[]{},;:!?."
+
+//nolint:gochecknoglobals
+var urlSchemes = []string{"http://", "https://", "ftp://"}
+
+// linkWords splits a sentence in the words which can be a link, angle brackets and | separate words
+// like in webRidge.nl <https://webridge.nl/> | info@webridge.nl
+func linkWords(v string) []string {
+	fields := strings.FieldsFunc(v, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '<' || r == '>' || r == '|'
+	})
+	words := fields[:0]
+	for _, field := range fields {
+		if word := strings.Trim(field, linkPunctuation); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+func (p *Parser) containsWebsite(v string) bool {
+	for _, word := range linkWords(v) {
+		if p.isURL(word) || p.isDomain(word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Parser) containsEmail(v string) bool {
+	for _, word := range linkWords(v) {
+		if p.isEmailAddress(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// isURL tells if the word is a link with a scheme like https://example.com/path,
+// the scheme makes it a link so the top level domain only has to look like one
+func (p *Parser) isURL(word string) bool {
+	for _, scheme := range urlSchemes {
+		if len(word) > len(scheme) && strings.EqualFold(word[:len(scheme)], scheme) {
+			labels, ok := domainLabels(hostOf(word[len(scheme):]))
+			return ok && (p.isTopLevelDomain(labels[len(labels)-1]) || isLetters(labels[len(labels)-1]))
+		}
+	}
+	return false
+}
+
+// isDomain tells if the word is a domain without a scheme like webRidge.nl or www.example.com/contact,
+// the top level domain has to be known so e.g. and version numbers like 2.0.1 are no domains
+func (p *Parser) isDomain(word string) bool {
+	if strings.ContainsRune(word, '@') {
+		return false
+	}
+	labels, ok := domainLabels(hostOf(word))
+	return ok && p.isTopLevelDomain(labels[len(labels)-1])
+}
+
+// isEmailAddress tells if the word is an email address like info@example.com or an internationalized
+// one like jürgen@müller.de, a mailto: or a label like e: before the address is ignored
+func (p *Parser) isEmailAddress(word string) bool {
+	at := strings.LastIndexByte(word, '@')
+	if at == -1 {
+		return false
+	}
+	local, domain := word[:at], word[at+1:]
+	if i := strings.LastIndexByte(local, ':'); i != -1 {
+		local = local[i+1:]
+	}
+	labels, ok := domainLabels(domain)
+	return isLocalPart(local) && ok && p.isTopLevelDomain(labels[len(labels)-1])
+}
+
+// isTopLevelDomain tells if the label is a known top level domain, a punycode one like xn--p1ai
+// or an internationalized one like рф
+func (p *Parser) isTopLevelDomain(label string) bool {
+	if p.extensions.has(label) {
+		return true
+	}
+	if isPunycode(label) {
+		return true
+	}
+	return isLetters(label) && !isASCII(label) && utf8.RuneCountInString(label) >= 2
+}
+
+// hostOf returns the host of a link without the path, query, fragment and port
+func hostOf(link string) string {
+	if i := strings.IndexAny(link, "/?#"); i != -1 {
+		link = link[:i]
+	}
+	if i := strings.LastIndexByte(link, ':'); i != -1 && isNumberWord(link[i+1:]) {
+		link = link[:i]
+	}
+	return link
+}
+
+// domainLabels returns the labels of a domain with at least two valid labels
+func domainLabels(domain string) ([]string, bool) {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return nil, false
+	}
+	for _, label := range labels {
+		if !isDomainLabel(label) {
+			return nil, false
+		}
+	}
+	return labels, true
+}
+
+// isDomainLabel tells if the label only contains letters, digits and hyphens and does not start
+// or end with a hyphen, letters can be any unicode letter for internationalized domains
+func isDomainLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if !isWordRune(r) && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// isPunycode tells if the label is an ascii encoded internationalized label like xn--bcher-kva
+func isPunycode(label string) bool {
+	if len(label) <= 4 || !strings.EqualFold(label[:4], "xn--") {
+		return false
+	}
+	for i := 4; i < len(label); i++ {
+		if !isDomainByte(label[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isLocalPart tells if the part before the @ of an email address is valid, letters can be any
+// unicode letter for internationalized addresses
+func isLocalPart(local string) bool {
+	if local == "" || len(local) > 64 || local[0] == '.' || local[len(local)-1] == '.' ||
+		strings.Contains(local, "..") {
+		return false
+	}
+	for _, r := range local {
+		if !isWordRune(r) && !strings.ContainsRune(".!#$%&'*+-/=?^_`{|}~", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+)
+
+func TestContainsWebsite(t *testing.T) {
+	p := defaultParser()
+	tests := map[string]bool{
+		"webRidge.nl <https://webridge.nl/>": true,
+		"*WEB     webRidge.nl*":              true,
+		"www.example.com/contact":            true,
+		"(example.co.uk)":                    true,
+		"http://intranet.corp:8080/wiki":     true,
+		"bücher.de":                          true,
+		"xn--bcher-kva.de":                   true,
+		"пример.рф":                          true,
+		"example.xn--p1ai":                   true,
+		"I will look at it e.g. tomorrow":    false,
+		"Upgraded to 2.0.1 yesterday":        false,
+		"See the file notes.txt":             false,
+		"info@example.com":                   false,
+		"-example-.com":                      false,
+		"Thanks.Regards":                     false,
+		"https://":                           false,
+	}
+	for sentence, expected := range tests {
+		if is := p.containsWebsite(sentence); is != expected {
+			t.Errorf("%v: expected: `%v` but is `%v`", sentence, expected, is)
+		}
+	}
+}
+
+func TestContainsEmail(t *testing.T) {
+	p := defaultParser()
+	tests := map[string]bool{
+		"info@webridge.nl":                  true,
+		"E: <karen.green+news@example.com>": true,
+		"[mailto:john@smith.org]":           true,
+		"e:info@webridge.nl":                true,
+		"jürgen@müller.de":                  true,
+		"用户@例子.广告":                          true,
+		"user@xn--mller-kva.de":             true,
+		"meet me @ 10.30":                   false,
+		"@example.com":                      false,
+		"john@localhost":                    false,
+		"john..smith@example.com":           false,
+		"john@example.unknowntld":           false,
+		"name@version 2.0":                  false,
+	}
+	for sentence, expected := range tests {
+		if is := p.containsEmail(sentence); is != expected {
+			t.Errorf("%v: expected: `%v` but is `%v`", sentence, expected, is)
+		}
+	}
+}
+
+func TestLinkSignatureLines(t *testing.T) {
+	p := defaultParser()
+	tests := map[string]bool{
+		"webRidge.nl <https://webridge.nl/>": true,
+		"E-mail: info@webridge.nl":           true,
+		"Web: www.webridge.nl":               true,
+		"We use version 1.2.3 now":           false,
+		"Just use e.g. the old one":          false,
+	}
+	for line, expected := range tests {
+		if is := p.isPossibleSignatureLine(line); is != expected {
+			t.Errorf("%v: expected: `%v` but is `%v`", line, expected, is)
+		}
+	}
+}
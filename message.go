@@ -0,0 +1,300 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrUnsupportedCharset is added to ParsedMessage.DecodeErrors when a body uses a charset which is not built in
+// and Options.CharsetReader does not support it either
+var ErrUnsupportedCharset = errors.New("email_reply_parser: unsupported charset")
+
+// ParsedMessage is a parsed RFC 5322 message
+type ParsedMessage struct {
+	// Header contains the From, To, Cc, Subject and Date of the message
+	Header *MessageHeader
+	// RawHeader contains all header fields of the message
+	RawHeader mail.Header
+	// Email is parsed from the text/plain part or from the text/html part when there is no text/plain part
+	Email *Email
+	// Reply is the text the sender actually wrote
+	Reply string
+	// HTML is nil when the message has no text/html part
+	HTML        *HTMLReply
+	Attachments []*Attachment
+	// DecodeErrors contains why a body could not be converted to UTF-8 e.g. an ErrUnsupportedCharset,
+	// the characters of such a body which are not ascii are replaced and the other body is used when it has none
+	DecodeErrors []error
+}
+
+// Attachment is a part of a message which is not the text or HTML body
+type Attachment struct {
+	Filename    string
+	ContentType string
+	// ContentID is set for inline images which are used in the HTML body like <img src="cid:...">
+	ContentID string
+	Inline    bool
+	Content   []byte
+}
+
+// ParseMessage reads a RFC 5322 message, picks the text/plain or text/html part
+// and parses the reply in it
+func ParseMessage(r io.Reader) (*ParsedMessage, error) {
+	return defaultParser().ParseMessage(r)
+}
+
+// ParseMessage reads a RFC 5322 message, picks the text/plain or text/html part
+// and parses the reply in it
+func (p *Parser) ParseMessage(r io.Reader) (*ParsedMessage, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	message, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	parsed := &ParsedMessage{RawHeader: message.Header}
+	parsed.Header = p.messageHeader(message.Header, rawHeader(raw))
+
+	var bodies messageBodies
+	err = p.walkPart(textproto.MIMEHeader(message.Header), message.Body, &bodies, parsed)
+	if err != nil {
+		return nil, err
+	}
+
+	if bodies.html != nil {
+		parsed.HTML = p.ParseHTML(*bodies.html)
+	}
+	switch {
+	// the HTML part is used when only the text/plain part lost characters
+	case bodies.text != nil && (!bodies.textLossy || bodies.html == nil || bodies.htmlLossy):
+		parsed.Email = p.ParseEmail(*bodies.text)
+	case parsed.HTML != nil:
+		parsed.Email = parsed.HTML.Email
+	default:
+		parsed.Email = p.ParseEmail("")
+	}
+	parsed.Reply = parsed.Email.Reply()
+	return parsed, nil
+}
+
+// messageBodies are the first text/plain and text/html parts of a message, a body is lossy
+// when its charset could not be decoded
+type messageBodies struct {
+	text      *string
+	html      *string
+	textLossy bool
+	htmlLossy bool
+}
+
+// walkPart decodes a part of the message and walks into the parts of multipart parts
+func (p *Parser) walkPart(header textproto.MIMEHeader, body io.Reader, bodies *messageBodies, parsed *ParsedMessage) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// RFC 2045 says a part without a valid content type is plain text
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.walkPart(part.Header, part, bodies, parsed); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := dispositionParams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	isBody := disposition != "attachment" && filename == ""
+	switch {
+	case isBody && mediaType == "text/plain" && bodies.text == nil:
+		text, lossy := p.decodeBody(params["charset"], content, parsed)
+		bodies.textLossy = lossy
+		// message bodies use CRLF line endings, the reply is returned with \n like a plain text body
+		text = strings.ReplaceAll(text, "\r\n", enter)
+		bodies.text = &text
+	case isBody && mediaType == "text/html" && bodies.html == nil:
+		html, lossy := p.decodeBody(params["charset"], content, parsed)
+		bodies.htmlLossy = lossy
+		bodies.html = &html
+	default:
+		parsed.Attachments = append(parsed.Attachments, &Attachment{
+			Filename:    p.decodeHeader(filename),
+			ContentType: mediaType,
+			ContentID:   strings.Trim(header.Get("Content-ID"), "<>"),
+			Inline:      disposition == "inline" || (disposition == "" && header.Get("Content-ID") != ""),
+			Content:     content,
+		})
+	}
+	return nil
+}
+
+// decodeBody converts a body to UTF-8, when the charset can not be decoded the error is added to the
+// message and only the ascii characters are kept so the rest of the message can still be parsed
+func (p *Parser) decodeBody(charset string, content []byte, parsed *ParsedMessage) (string, bool) {
+	decoded, err := p.decodeCharset(charset, content)
+	if err == nil {
+		return decoded, false
+	}
+	parsed.DecodeErrors = append(parsed.DecodeErrors, err)
+	return decodeASCII(content), true
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
+
+// messageHeader returns the From, To, Cc, Subject and Date of the message
+func (p *Parser) messageHeader(header mail.Header, raw string) *MessageHeader {
+	addressParser := mail.AddressParser{WordDecoder: p.wordDecoder()}
+	messageHeader := &MessageHeader{
+		Subject: p.decodeHeader(header.Get("Subject")),
+		Raw:     raw,
+	}
+	if from, err := addressParser.ParseList(header.Get("From")); err == nil && len(from) > 0 {
+		messageHeader.From = from[0]
+	}
+	if to, err := addressParser.ParseList(header.Get("To")); err == nil {
+		messageHeader.To = to
+	}
+	if cc, err := addressParser.ParseList(header.Get("Cc")); err == nil {
+		messageHeader.Cc = cc
+	}
+	if date, err := header.Date(); err == nil {
+		messageHeader.Date = date
+	}
+	return messageHeader
+}
+
+// rawHeader returns the header lines of a message without the body
+func rawHeader(raw []byte) string {
+	for _, separator := range []string{"\r\n\r\n", "\n\n"} {
+		if i := bytes.Index(raw, []byte(separator)); i != -1 {
+			return string(raw[:i])
+		}
+	}
+	return string(raw)
+}
+
+func (p *Parser) wordDecoder() *mime.WordDecoder {
+	return &mime.WordDecoder{CharsetReader: p.charsetReaderFor}
+}
+
+// decodeHeader decodes encoded words like =?iso-8859-1?q?caf=E9?= and returns the value as is when it can not be decoded
+func (p *Parser) decodeHeader(value string) string {
+	decoded, err := p.wordDecoder().DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// charsetReaderFor returns a reader which converts the charset to UTF-8
+func (p *Parser) charsetReaderFor(charset string, input io.Reader) (io.Reader, error) {
+	content, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := p.decodeCharset(charset, content)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(decoded), nil
+}
+
+// decodeCharset converts the content to UTF-8, UTF-8, US-ASCII, ISO-8859-1 and Windows-1252
+// are built in and other charsets are passed to Options.CharsetReader
+func (p *Parser) decodeCharset(charset string, content []byte) (string, error) {
+	switch strings.ToLower(strings.Trim(charset, `" `)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return strings.ToValidUTF8(string(content), string(utf8.RuneError)), nil
+	case "iso-8859-1", "iso8859-1", "latin1", "latin-1", "l1":
+		return decodeSingleByte(content, nil), nil
+	case "windows-1252", "cp1252":
+		return decodeSingleByte(content, &windows1252), nil
+	}
+
+	if p.charsetReader == nil {
+		return "", fmt.Errorf("%w: %v", ErrUnsupportedCharset, charset)
+	}
+	reader, err := p.charsetReader(charset, bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+// decodeSingleByte converts a single byte charset to UTF-8, the bytes 0x80 till 0x9F
+// are looked up in the table and the others are the same as in ISO-8859-1
+func decodeSingleByte(content []byte, table *[32]rune) string {
+	var b strings.Builder
+	b.Grow(len(content))
+	for _, c := range content {
+		if table != nil && c >= 0x80 && c <= 0x9F {
+			b.WriteRune(table[c-0x80])
+			continue
+		}
+		b.WriteRune(rune(c))
+	}
+	return b.String()
+}
+
+// decodeASCII keeps the ascii characters and replaces the other bytes with U+FFFD
+func decodeASCII(content []byte) string {
+	var b strings.Builder
+	b.Grow(len(content))
+	for _, c := range content {
+		if c >= utf8.RuneSelf {
+			b.WriteRune(utf8.RuneError)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// windows1252 contains the characters of the bytes 0x80 till 0x9F, the unused bytes are the same as in ISO-8859-1
+//
+//nolint:gochecknoglobals
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
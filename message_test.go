@@ -0,0 +1,157 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseMessageMultipart(t *testing.T) {
+	file, err := os.Open("./dataset/message/multipart.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	message, err := ParseMessage(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Reply != "Merci, le café est prêt." {
+		t.Errorf("expected: `%v` but is `%v`", "Merci, le café est prêt.", message.Reply)
+	}
+	if message.HTML == nil || message.HTML.HTML != `<div dir="ltr">Merci, le café est prêt.</div>` {
+		t.Errorf("expected the HTML reply but is `%v`", message.HTML)
+	}
+
+	header := message.Header
+	if header.Subject != "Re: café" {
+		t.Errorf("expected: `%v` but is `%v`", "Re: café", header.Subject)
+	}
+	if header.From == nil || header.From.Name != "José Smith" || header.From.Address != "jose@example.org" {
+		t.Errorf("expected José Smith <jose@example.org> but is `%v`", header.From)
+	}
+	if len(header.To) != 2 || len(header.Cc) != 1 {
+		t.Errorf("expected 2 to and 1 cc addresses but is `%v` `%v`", header.To, header.Cc)
+	}
+	if !header.Date.Equal(time.Date(2019, time.August, 26, 15, 1, 2, 0, time.UTC)) {
+		t.Errorf("expected: `%v` but is `%v`", "2019-08-26 15:01:02 UTC", header.Date)
+	}
+	if !strings.HasPrefix(header.Raw, "From: ") || strings.Contains(header.Raw, "--mixed") {
+		t.Errorf("expected only the header lines but is `%v`", header.Raw)
+	}
+	if message.RawHeader.Get("Message-ID") != "<1234@example.org>" {
+		t.Errorf("expected: `%v` but is `%v`", "<1234@example.org>", message.RawHeader.Get("Message-ID"))
+	}
+
+	if len(message.Attachments) != 1 {
+		t.Fatalf("expected 1 attachment but got %v", len(message.Attachments))
+	}
+	attachment := message.Attachments[0]
+	if attachment.Filename != "menu.pdf" || attachment.ContentType != "application/pdf" || attachment.Inline {
+		t.Errorf("expected the attachment menu.pdf but is `%v`", attachment)
+	}
+	if string(attachment.Content) != "%PDF-1.4 fake" {
+		t.Errorf("expected: `%v` but is `%v`", "%PDF-1.4 fake", string(attachment.Content))
+	}
+}
+
+func TestParseMessageWindows1252(t *testing.T) {
+	file, err := os.Open("./dataset/message/windows1252.eml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	message, err := ParseMessage(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "That’s “great” – thanks €5"
+	if message.Reply != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, message.Reply)
+	}
+	if message.HTML != nil {
+		t.Errorf("expected no HTML part")
+	}
+}
+
+const koi8Message = "From: a@example.org\r\n" +
+	"Subject: test\r\n" +
+	"Content-Type: text/plain; charset=koi8-r\r\n" +
+	"\r\n" +
+	"\xf0\xd2\xc9\xd7\xc5\xd4\r\n"
+
+func TestParseMessageCharsetReader(t *testing.T) {
+	message, err := ParseMessage(strings.NewReader(koi8Message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(message.DecodeErrors) != 1 || !errors.Is(message.DecodeErrors[0], ErrUnsupportedCharset) {
+		t.Errorf("expected: `%v` but is `%v`", ErrUnsupportedCharset, message.DecodeErrors)
+	}
+	if message.Reply != strings.Repeat("\uFFFD", 6) {
+		t.Errorf("expected: `%v` but is `%v`", strings.Repeat("\uFFFD", 6), message.Reply)
+	}
+
+	parser := NewParser(Options{
+		CharsetReader: func(charset string, input io.Reader) (io.Reader, error) {
+			if charset != "koi8-r" {
+				t.Errorf("expected: `%v` but is `%v`", "koi8-r", charset)
+			}
+			return strings.NewReader("Привет"), nil
+		},
+	})
+	message, err = parser.ParseMessage(strings.NewReader(koi8Message))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Reply != "Привет" {
+		t.Errorf("expected: `%v` but is `%v`", "Привет", message.Reply)
+	}
+}
+
+func TestParseMessageUnsupportedHTMLCharset(t *testing.T) {
+	message, err := ParseMessage(strings.NewReader("From: a@example.org\r\n" +
+		"Content-Type: multipart/alternative; boundary=b\r\n" +
+		"\r\n" +
+		"--b\r\nContent-Type: text/plain; charset=us-ascii\r\n\r\nYes\r\n" +
+		"--b\r\nContent-Type: text/html; charset=gb2312\r\n\r\n<p>\xca\xc7</p>\r\n" +
+		"--b--\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Reply != "Yes" || len(message.DecodeErrors) != 1 {
+		t.Errorf("expected the reply of the text part and a decode error but is `%v` `%v`", message.Reply, message.DecodeErrors)
+	}
+
+	// the HTML part is used when only the text part can not be decoded
+	message, err = ParseMessage(strings.NewReader("From: a@example.org\r\n" +
+		"Content-Type: multipart/alternative; boundary=b\r\n" +
+		"\r\n" +
+		"--b\r\nContent-Type: text/plain; charset=iso-8859-15\r\n\r\nCaf\xe9\r\n" +
+		"--b\r\nContent-Type: text/html; charset=utf-8\r\n\r\n<p>Café</p>\r\n" +
+		"--b--\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Reply != "Café" || message.Email != message.HTML.Email {
+		t.Errorf("expected: `%v` but is `%v`", "Café", message.Reply)
+	}
+}
+
+func TestParseMessageHTMLOnly(t *testing.T) {
+	message, err := ParseMessage(strings.NewReader("From: a@example.org\r\n" +
+		"Content-Type: text/html; charset=utf-8\r\n" +
+		"\r\n" +
+		`<p>Yes</p><blockquote type="cite"><p>Coffee?</p></blockquote>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Reply != "Yes" || message.Email != message.HTML.Email {
+		t.Errorf("expected the reply of the HTML part but is `%v`", message.Reply)
+	}
+}
@@ -0,0 +1,72 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// normalizeLine returns the line the way the detection looks at it, the carriage return of a CRLF
+// line ending is removed, unicode spaces like the no-break space and the narrow no-break space
+// French uses before a colon become a normal space, zero width characters are removed
+// and decomposed letters like e followed by a combining acute are composed to é
+func normalizeLine(line string) string {
+	line = strings.TrimSuffix(line, "\r")
+	if isNormalized(line) {
+		return line
+	}
+
+	var b strings.Builder
+	b.Grow(len(line))
+	var last rune = -1
+	for _, r := range line {
+		switch {
+		case isZeroWidth(r):
+			continue
+		case r != ' ' && unicode.Is(unicode.Zs, r):
+			r = ' '
+		case last != -1 && unicode.Is(unicode.Mn, r):
+			if composed, ok := compositions[[2]rune{last, r}]; ok {
+				last = composed
+				continue
+			}
+		}
+		if last != -1 {
+			b.WriteRune(last)
+		}
+		last = r
+	}
+	if last != -1 {
+		b.WriteRune(last)
+	}
+	return b.String()
+}
+
+// isNormalized tells if the line only has ASCII characters which normalizeLine would keep
+func isNormalized(line string) bool {
+	for i := 0; i < len(line); i++ {
+		if line[i] >= utf8.RuneSelf || line[i] == '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+// isZeroWidth detects characters which are not visible like the zero width space,
+// the byte order mark and the soft hyphen
+func isZeroWidth(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff', '\u00ad':
+		return true
+	}
+	return false
+}
+
+// foldCase lowercases the text for matching, the German ß is folded to ss so Grüße matches GRÜSSE
+func foldCase(v string) string {
+	v = strings.ToLower(v)
+	if strings.ContainsRune(v, 'ß') {
+		v = strings.ReplaceAll(v, "ß", "ss")
+	}
+	return v
+}
@@ -0,0 +1,69 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+)
+
+func TestNormalizeLine(t *testing.T) {
+	tests := map[string]string{
+		"plain text":                      "plain text",
+		"crlf\r":                          "crlf",
+		"no\u00a0break":                   "no break",
+		"a écrit\u202f:":                  "a écrit :",
+		"zero\u200bwidth\ufeff":           "zerowidth",
+		"Gru\u0308\u00dfe":                "Grüße",
+		"Vie\u0302\u0301t":                "Viết",
+		"unknown mark q\u0301":            "unknown mark q\u0301",
+		"\u0301 mark without letter":      "\u0301 mark without letter",
+		"soft\u00adhyphen and\u2007space": "softhyphen and space",
+	}
+	for line, expected := range tests {
+		if normalized := normalizeLine(line); normalized != expected {
+			t.Errorf("expected: `%q` but is `%q`", expected, normalized)
+		}
+	}
+}
+
+func TestFoldCase(t *testing.T) {
+	if foldCase("GRÜSSE") != foldCase("Grüße") {
+		t.Errorf("expected: `%v` but is `%v`", foldCase("GRÜSSE"), foldCase("Grüße"))
+	}
+}
+
+func TestNormalizedDetection(t *testing.T) {
+	tests := map[string]string{
+		"crlf": "Thanks for the update.\r\n\r\n" +
+			"On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\r\n" +
+			"> The deploy is done.\r\n",
+		"narrow no-break space before the colon": "Merci !\n\n" +
+			"Le lun. 4 nov. 2013 à 16:29, John Smith <john@smith.org> a écrit\u202f:\n" +
+			"> The deploy is done.",
+		"no-break spaces": "Thanks!\n\n" +
+			"On\u00a0Mon, Nov 4, 2013 at 4:29\u00a0PM, John Smith <john@smith.org> wrote:\n" +
+			"> The deploy is done.",
+		"zero width space":    "Thanks!\n\nSent from my\u200b iPhone",
+		"decomposed greeting": "Danke für das Update.\n\nViele Gru\u0308\u00dfe\nKaren Green",
+		"folded greeting":     "Danke für das Update.\n\nVIELE GRÜSSE\nKaren Green",
+	}
+	expected := map[string]string{
+		"crlf":                                   "Thanks for the update.",
+		"narrow no-break space before the colon": "Merci !",
+		"no-break spaces":                        "Thanks!",
+		"zero width space":                       "Thanks!",
+		"decomposed greeting":                    "Danke für das Update.",
+		"folded greeting":                        "Danke für das Update.",
+	}
+	for name, mail := range tests {
+		if reply := Parse(mail); reply != expected[name] {
+			t.Errorf("%v: expected: `%q` but is `%q`", name, expected[name], reply)
+		}
+	}
+}
+
+func TestNormalizationKeepsOriginal(t *testing.T) {
+	mail := "First line\r\nSecond\u00a0line\r\n\r\n-- \r\nKaren"
+	expected := "First line\r\nSecond\u00a0line"
+	if reply := Parse(mail); reply != expected {
+		t.Errorf("expected: `%q` but is `%q`", expected, reply)
+	}
+}
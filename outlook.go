@@ -0,0 +1,111 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+)
+
+// outlookHeaderLength returns the amount of lines of an Outlook quote header which starts at the line
+// or 0 when there is no Outlook quote header, the header is one of
+//
+// -----Original Message-----
+// From: John Smith [mailto:john@smith.org]
+// Sent: Monday, August 26, 2019 4:37 PM
+//
+// ________________________________
+// From: John Smith <john@smith.org>
+// Sent: Monday, August 26, 2019 4:37 PM
+//
+// From: John Smith <john@smith.org>
+// Sent: Monday, August 26, 2019 4:37 PM
+// To: Karen Green <karen@webby.com>
+// Subject: Invoice
+func (p *Parser) outlookHeaderLength(lineIndex int, lines []*Line) int {
+	line := lines[lineIndex]
+	if line.IsEmpty || line.IsQuoted {
+		return 0
+	}
+
+	isMarker := p.isOriginalMessageMarker(line.ContentStripped)
+	isRule := isUnderscoreRule(line.ContentStripped)
+	headerStart := lineIndex
+	if isMarker || isRule {
+		headerStart++
+		for headerStart < len(lines) && lines[headerStart].IsEmpty {
+			headerStart++
+		}
+	}
+
+	var fields [headerDate + 1]bool
+	var used int
+	if headerStart < len(lines) {
+		fields, used = p.outlookHeaderFields(headerStart, lines)
+	}
+	switch {
+	case isMarker && used == 0:
+		return 1
+	case isMarker:
+		return headerStart - lineIndex + used
+	case isRule && fields[headerFrom]:
+		return headerStart - lineIndex + used
+	case !isRule && fields[headerFrom] && fields[headerDate] && (fields[headerTo] || fields[headerSubject]) &&
+		p.hasHeaderValues(lines[headerStart:headerStart+used]):
+		return used
+	}
+	return 0
+}
+
+// hasHeaderValues tells if the From line of a header contains an address or the Sent or Date line a date,
+// a header without a marker above it could otherwise be text like From: now on we deploy on Mondays.
+func (p *Parser) hasHeaderValues(lines []*Line) bool {
+	header, _ := p.parseMessageHeader(lines)
+	return header != nil && (header.From != nil || !header.Date.IsZero())
+}
+
+// isOriginalMessageMarker detects lines like -----Original Message-----
+func (p *Parser) isOriginalMessageMarker(stripped string) bool {
+	if !strings.HasPrefix(stripped, "--") {
+		return false
+	}
+	return isOneOf(foldCase(strings.Trim(stripped, "-_= ")), p.language.OriginalMessage)
+}
+
+// isUnderscoreRule detects the ________________________________ line Outlook puts above the header
+func isUnderscoreRule(stripped string) bool {
+	return len(stripped) >= 8 && strings.Trim(stripped, "_") == ""
+}
+
+// outlookHeaderFields returns the fields of the header lines which start at the line and the amount
+// of lines they use, they are looked up in the header runs, the line has to be a header line
+func (p *Parser) outlookHeaderFields(lineIndex int, lines []*Line) ([headerDate + 1]bool, int) {
+	var fields [headerDate + 1]bool
+	runs, first := p.headerRunsOf(lines)
+	start := first + lineIndex
+	used := runs.length[start]
+	// the header ends at the last line like parseMessageHeader does
+	if used > len(lines)-lineIndex {
+		used = len(lines) - lineIndex
+	}
+	for field := range fields {
+		fields[field] = runs.fieldCounts[field][start+used] > runs.fieldCounts[field][start]
+	}
+	return fields, used
+}
+
+// parseOutlookHeader returns the fields of an Outlook quote header or nil when the lines do not start with one
+func (p *Parser) parseOutlookHeader(lines []*Line) *MessageHeader {
+	if len(lines) == 0 || p.outlookHeaderLength(0, lines) == 0 {
+		return nil
+	}
+	headerStart := 0
+	if p.isOriginalMessageMarker(lines[0].ContentStripped) || isUnderscoreRule(lines[0].ContentStripped) {
+		headerStart++
+		for headerStart < len(lines) && lines[headerStart].IsEmpty {
+			headerStart++
+		}
+	}
+	if headerStart == len(lines) || !p.isHeaderLine(lines[headerStart]) {
+		return nil
+	}
+	header, _ := p.parseMessageHeader(lines[headerStart:])
+	return header
+}
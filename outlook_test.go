@@ -0,0 +1,141 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOutlookQuoteHeaderFields(t *testing.T) {
+	email := ParseEmail(outlookMail)
+	expected := []FragmentKind{FragmentReply, FragmentSignature, FragmentQuoteHeader, FragmentQuotedReply}
+	assertFragmentKinds(t, email, expected)
+
+	if email.Reply() != "The invoice is paid." {
+		t.Errorf("expected: `%v` but is `%v`", "The invoice is paid.", email.Reply())
+	}
+	header := email.Fragments[2].Header
+	if header == nil {
+		t.Fatalf("expected the fields of the Outlook header")
+	}
+	if header.From == nil || header.From.Name != "Karen Green" || header.From.Address != "karen@webby.com" {
+		t.Errorf("expected Karen Green <karen@webby.com> but is `%v`", header.From)
+	}
+	if len(header.To) != 1 || header.Subject != "Invoice" {
+		t.Errorf("expected one to address and subject Invoice but is `%v` `%v`", header.To, header.Subject)
+	}
+	if !header.Date.Equal(time.Date(2019, time.August, 26, 16, 37, 0, 0, time.UTC)) {
+		t.Errorf("expected: `%v` but is `%v`", "2019-08-26 16:37", header.Date)
+	}
+	attribution := email.Fragments[2].Attribution
+	if attribution == nil || attribution.Name != "Karen Green" {
+		t.Errorf("expected the attribution of Karen Green but is `%v`", attribution)
+	}
+}
+
+const outlookMail = `The invoice is paid.
+
+Regards,
+John
+
+-----Original Message-----
+From: Karen Green [mailto:karen@webby.com]
+Sent: Monday, August 26, 2019 4:37 PM
+To: John Smith <john.smith@example.org>
+Subject: Invoice
+
+Could you pay the invoice?
+
+Thanks,
+Karen
+`
+
+func TestOutlookLocalizedHeaders(t *testing.T) {
+	tests := map[string]string{
+		"dutch": `Is betaald.
+
+*Van:* Karen Green <karen@webby.com>
+*Verzonden:* maandag 26 augustus 2019 16:37
+*Aan:* John Smith <john.smith@example.org>
+*Onderwerp:* Factuur
+
+Kun je de factuur betalen?`,
+		"german": `Ist bezahlt.
+
+Von: Karen Green <karen@webby.com>
+Gesendet: Montag, 26. August 2019 16:37
+An: John Smith <john.smith@example.org>
+Betreff: Rechnung
+
+Kannst du die Rechnung bezahlen?`,
+		"french": `C'est payé.
+
+________________________________
+De : Karen Green <karen@webby.com>
+Envoyé : lundi 26 août 2019 16:37
+À : John Smith <john.smith@example.org>
+Objet : Facture
+
+Peux-tu payer la facture ?`,
+	}
+	expected := map[string]string{
+		"dutch":  "Is betaald.",
+		"german": "Ist bezahlt.",
+		"french": "C'est payé.",
+	}
+	for name, mail := range tests {
+		email := ParseEmail(mail)
+		if email.Reply() != expected[name] {
+			t.Errorf("%v: expected: `%v` but is `%v`", name, expected[name], email.Reply())
+		}
+		var header *MessageHeader
+		for _, fragment := range email.Fragments {
+			if fragment.Kind == FragmentQuoteHeader {
+				header = fragment.Header
+			}
+		}
+		if header == nil || header.From == nil || header.From.Address != "karen@webby.com" {
+			t.Errorf("%v: expected the header from karen@webby.com but is `%v`", name, header)
+		}
+	}
+}
+
+func TestOutlookHeaderNeedsDateAndRecipient(t *testing.T) {
+	// a single From: line in a reply is not a quote header
+	mail := `Please check the mail
+From: the support team
+about the outage.`
+	if Parse(mail) != mail {
+		t.Errorf("expected: `%v` but is `%v`", mail, Parse(mail))
+	}
+}
+
+func TestOutlookHeaderNeedsAddressOrDate(t *testing.T) {
+	mail := "Hi team,\n\nFrom: now on we deploy on Mondays.\nDate: to be decided.\nTo: all developers\n\nThanks"
+	if Parse(mail) != mail {
+		t.Errorf("expected: `%v` but is `%v`", mail, Parse(mail))
+	}
+
+	// Outlook leaves out the address of colleagues but the date is still readable
+	mail = "See below\n\nFrom: John Smith\nSent: Monday, August 26, 2019 4:37 PM\nTo: Karen Green\nSubject: Invoice\n\nPlease pay the invoice"
+	if Parse(mail) != "See below" {
+		t.Errorf("expected: `%v` but is `%v`", "See below", Parse(mail))
+	}
+}
+
+func TestOutlookHeaderRuns(t *testing.T) {
+	parser := defaultParser()
+	lines := parser.plainMailToLines(outlookMail)
+	for i := range lines {
+		if !parser.isHeaderLine(lines[i]) {
+			continue
+		}
+		// the header runs are looked up for the whole email, a prefix and the lines from the header on
+		for _, sub := range [][]*Line{lines, lines[:len(lines)-1], lines[i:]} {
+			lineIndex := i - sub[0].Index
+			_, used := parser.outlookHeaderFields(lineIndex, sub)
+			if _, expected := parser.parseMessageHeader(sub[lineIndex:]); used != expected {
+				t.Errorf("line %v: expected: `%v` but is `%v`", i, expected, used)
+			}
+		}
+	}
+}
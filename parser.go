@@ -0,0 +1,152 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"io"
+)
+
+// Options changes the behaviour of a Parser, word lists which are nil and thresholds which
+// are zero fall back to the defaults so Options{} behaves the same as Parse
+type Options struct {
+	// Languages are the codes of the registered languages the parser uses e.g. []string{"en"},
+	// all registered languages are used when it is empty, codes which are not registered are ignored
+	// and Validate returns an error for them
+	Languages []string
+
+	// Greetings like best regards which start a signature
+	Greetings []string
+	// On and Wrote are the words of a quote header like On DATE, NAME <EMAIL> wrote:
+	On    []string
+	Wrote []string
+	// Sent and MailPrograms are the words of a device footer like Sent from my iPhone
+	Sent         []string
+	MailPrograms []string
+	// Forwarded are the words of a forward marker like ---------- Forwarded message ----------
+	Forwarded []string
+	// Extensions are the top level domains used to detect websites in signatures
+	Extensions []string
+
+	// SignatureMatchPercentage is the percentage of lines after a possible signature start
+	// which have to look like a signature line, defaults to 70
+	SignatureMatchPercentage float64
+	// MaxDisclaimerLines is the maximum amount of filled lines after a signature which are
+	// still seen as a disclaimer, defaults to 6
+	MaxDisclaimerLines int
+
+	// KeepSignature keeps the signature and disclaimer in the reply
+	KeepSignature bool
+	// KeepDeviceFooter keeps lines like Sent from my iPhone in the reply
+	KeepDeviceFooter bool
+
+	// QuotePrefixes are the characters which quote a line e.g. []string{">", "|", ":"}, defaults to >,
+	// characters other than > need a space after them
+	QuotePrefixes []string
+
+	// CharsetReader converts the charsets which are not built in to UTF-8 in ParseMessage,
+	// UTF-8, US-ASCII, ISO-8859-1 and Windows-1252 are built in
+	CharsetReader func(charset string, input io.Reader) (io.Reader, error)
+}
+
+// DefaultOptions returns the options used by Parse, use it to extend the default word lists e.g.
+// options.Greetings = append(options.Greetings, "cheers")
+// when languages are given the word lists only contain the words of those languages
+func DefaultOptions(languages ...string) Options {
+	language := mergeLanguages(languagesByCode(languages))
+	return Options{
+		Languages:                copyStrings(languages),
+		Greetings:                copyStrings(language.Greetings),
+		On:                       copyStrings(language.On),
+		Wrote:                    copyStrings(language.Wrote),
+		Sent:                     copyStrings(language.Sent),
+		MailPrograms:             copyStrings(mailPrograms),
+		Forwarded:                copyStrings(language.Forwarded),
+		Extensions:               copyStrings(extensions),
+		SignatureMatchPercentage: defaultSignatureMatchPercentage,
+		MaxDisclaimerLines:       defaultMaxDisclaimerLines,
+		QuotePrefixes:            copyStrings(defaultQuotePrefixes),
+	}
+}
+
+const (
+	defaultSignatureMatchPercentage = 70
+	defaultMaxDisclaimerLines       = 6
+)
+
+// Parser parses emails with its own word lists and settings,
+// it is not changed after NewParser so it is safe for concurrent use
+type Parser struct {
+	// language contains the words of all languages the parser uses
+	language Language
+
+	// the word lists compiled for matching, they are shared with parsers which use the same words
+	greetings    *phraseMatcher
+	on           *phraseMatcher
+	wrote        *phraseMatcher
+	quoting      *phraseMatcher
+	sent         *phraseMatcher
+	forwarded    *phraseMatcher
+	mailPrograms *phraseMatcher
+	extensions   *domainMatcher
+	// signatureLabels are the labels like tel which are followed by a value in a signature
+	signatureLabels *phraseMatcher
+
+	signatureMatchPercentage float64
+	maxDisclaimerLines       int
+
+	quotePrefixes []string
+
+	replyKinds    []FragmentKind
+	charsetReader func(charset string, input io.Reader) (io.Reader, error)
+}
+
+// NewParser returns a Parser which uses the given options
+func NewParser(options Options) *Parser {
+	defaults := DefaultOptions(options.Languages...)
+	language := mergeLanguages(languagesByCode(options.Languages))
+	language.Greetings = copyStringsOr(options.Greetings, defaults.Greetings)
+	language.On = copyStringsOr(options.On, defaults.On)
+	language.Wrote = copyStringsOr(options.Wrote, defaults.Wrote)
+	language.Sent = copyStringsOr(options.Sent, defaults.Sent)
+	language.Forwarded = copyStringsOr(options.Forwarded, defaults.Forwarded)
+
+	p := &Parser{
+		language:                 language,
+		greetings:                compilePhrases(language.Greetings),
+		on:                       compilePhrases(language.On),
+		wrote:                    compilePhrases(language.Wrote),
+		quoting:                  compilePhrases(language.Quoting),
+		sent:                     compilePhrases(language.Sent),
+		forwarded:                compilePhrases(language.Forwarded),
+		mailPrograms:             compilePhrases(copyStringsOr(options.MailPrograms, defaults.MailPrograms)),
+		extensions:               compileDomains(copyStringsOr(options.Extensions, defaults.Extensions)),
+		signatureLabels:          compilePhrases(language.SignatureLabels),
+		signatureMatchPercentage: options.SignatureMatchPercentage,
+		maxDisclaimerLines:       options.MaxDisclaimerLines,
+		quotePrefixes:            copyStringsOr(options.QuotePrefixes, defaults.QuotePrefixes),
+		replyKinds:               []FragmentKind{FragmentReply},
+		charsetReader:            options.CharsetReader,
+	}
+	if p.signatureMatchPercentage == 0 {
+		p.signatureMatchPercentage = defaults.SignatureMatchPercentage
+	}
+	if p.maxDisclaimerLines == 0 {
+		p.maxDisclaimerLines = defaults.MaxDisclaimerLines
+	}
+	if options.KeepSignature {
+		p.replyKinds = append(p.replyKinds, FragmentSignature, FragmentDisclaimer)
+	}
+	if options.KeepDeviceFooter {
+		p.replyKinds = append(p.replyKinds, FragmentDeviceFooter)
+	}
+	return p
+}
+
+func copyStrings(a []string) []string {
+	return append([]string(nil), a...)
+}
+
+func copyStringsOr(a []string, fallback []string) []string {
+	if a == nil {
+		return copyStrings(fallback)
+	}
+	return copyStrings(a)
+}
@@ -0,0 +1,70 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestParserKeepSignature(t *testing.T) {
+	parser := NewParser(Options{KeepSignature: true})
+	content := parser.Parse(abishhekMail)
+	expected := "Hi\n\n-Abhishek Kona\n\n\n" +
+		"_______________________________________________\n" +
+		"riak-users mailing list\n" +
+		"riak-users@lists.basho.com\n" +
+		"http://lists.basho.com/mailman/listinfo/riak-users_lists.basho.com"
+	if content != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, content)
+	}
+}
+
+func TestParserKeepDeviceFooter(t *testing.T) {
+	parser := NewParser(Options{KeepDeviceFooter: true})
+	content := parser.Parse(deviceFooterMail)
+	expected := "Sounds good, see you tomorrow!\n\nSent from my iPhone"
+	if content != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, content)
+	}
+
+	if Parse(deviceFooterMail) != "Sounds good, see you tomorrow!" {
+		t.Errorf("default parser should strip the device footer")
+	}
+}
+
+func TestParserGreetings(t *testing.T) {
+	mail := "Thanks for the update\n\ncheers,\njs"
+
+	if Parse(mail) != mail {
+		t.Errorf("cheers is not a default greeting: `%v`", Parse(mail))
+	}
+
+	options := DefaultOptions()
+	options.Greetings = append(options.Greetings, "cheers")
+	parser := NewParser(options)
+	expected := "Thanks for the update"
+	if content := parser.Parse(mail); content != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, content)
+	}
+}
+
+func TestParserSignatureMatchPercentage(t *testing.T) {
+	parser := NewParser(Options{SignatureMatchPercentage: 100})
+	if parser.Parse(karenMail) == Parse(karenMail) {
+		t.Errorf("Karen's signature should not be detected when all lines have to match")
+	}
+}
+
+func TestParserConcurrentUse(t *testing.T) {
+	parser := NewParser(DefaultOptions())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if content := parser.Parse(richardReverseMail); content != ":+1:" {
+				t.Errorf("expected: `%v` but is `%v`", ":+1:", content)
+			}
+		}()
+	}
+	wg.Wait()
+}
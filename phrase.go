@@ -0,0 +1,174 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// phraseMatcher finds the phrases of a word list in a text with a hash lookup per phrase length
+// instead of comparing every phrase, the phrases are case folded
+type phraseMatcher struct {
+	phrases map[string]bool
+	// lengths are the different byte lengths of the phrases, longest first
+	lengths []int
+}
+
+// maxCompiledMatchers is the maximum amount of matchers which are kept for sharing, parsers with custom
+// word lists like one per tenant compile their own matchers when the cache is full
+const maxCompiledMatchers = 64
+
+// matcherCache keeps the compiled matchers of the first word lists which are used
+// so parsers with the same words share them
+type matcherCache struct {
+	mutex    sync.Mutex
+	matchers map[string]interface{}
+}
+
+func (c *matcherCache) load(key string) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	m, ok := c.matchers[key]
+	return m, ok
+}
+
+// store keeps the matcher when there is room and returns the matcher which is kept for the key
+func (c *matcherCache) store(key string, m interface{}) interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if existing, ok := c.matchers[key]; ok {
+		return existing
+	}
+	if c.matchers == nil {
+		c.matchers = map[string]interface{}{}
+	}
+	if len(c.matchers) < maxCompiledMatchers {
+		c.matchers[key] = m
+	}
+	return m
+}
+
+//nolint:gochecknoglobals
+var compiledPhrases matcherCache
+
+// compilePhrases returns the matcher of the phrases and compiles it the first time the phrases are used
+func compilePhrases(phrases []string) *phraseMatcher {
+	key := strings.Join(phrases, "\x00")
+	if m, ok := compiledPhrases.load(key); ok {
+		return m.(*phraseMatcher)
+	}
+
+	m := &phraseMatcher{phrases: map[string]bool{}}
+	seen := map[int]bool{}
+	for _, phrase := range phrases {
+		phrase = foldCase(phrase)
+		if phrase == "" {
+			continue
+		}
+		m.phrases[phrase] = true
+		if !seen[len(phrase)] {
+			seen[len(phrase)] = true
+			m.lengths = append(m.lengths, len(phrase))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(m.lengths)))
+
+	return compiledPhrases.store(key, m).(*phraseMatcher)
+}
+
+// matchAt returns the length of the longest phrase which starts at the index of the folded text or 0,
+// the phrase has to start at the start of a word but can end in the middle of one because the words
+// are stems of inflected words like weitergeleitet in weitergeleiteten
+func (m *phraseMatcher) matchAt(text string, i int) int {
+	if !isWordBoundary(text, i) {
+		return 0
+	}
+	for _, length := range m.lengths {
+		if end := i + length; end <= len(text) && m.phrases[text[i:end]] {
+			return length
+		}
+	}
+	return 0
+}
+
+// prefixLength returns the length of the phrase the folded text starts with or 0
+func (m *phraseMatcher) prefixLength(text string) int {
+	return m.matchAt(text, 0)
+}
+
+// hasPrefixWord tells if the folded text starts with one of the phrases followed by a space
+func (m *phraseMatcher) hasPrefixWord(text string) bool {
+	for _, length := range m.lengths {
+		if length < len(text) && text[length] == ' ' && m.phrases[text[:length]] {
+			return true
+		}
+	}
+	return false
+}
+
+// longest returns the byte length of the longest phrase or 0
+func (m *phraseMatcher) longest() int {
+	if len(m.lengths) == 0 {
+		return 0
+	}
+	return m.lengths[0]
+}
+
+// contains tells if one of the phrases is in the folded text at the start of a word
+func (m *phraseMatcher) contains(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if !utf8.RuneStart(text[i]) {
+			continue
+		}
+		if m.matchAt(text, i) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isWordBoundary tells if the index is not between two letters or digits
+func isWordBoundary(text string, i int) bool {
+	if i == 0 || i == len(text) {
+		return true
+	}
+	before, _ := utf8.DecodeLastRuneInString(text[:i])
+	after, _ := utf8.DecodeRuneInString(text[i:])
+	return !isWordRune(before) || !isWordRune(after)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// domainMatcher detects top level domains with one hash lookup per dot in a word
+type domainMatcher struct {
+	extensions map[string]bool
+}
+
+//nolint:gochecknoglobals
+var compiledDomains matcherCache
+
+// compileDomains returns the matcher of the top level domains, parsers with the same domains share it
+func compileDomains(extensions []string) *domainMatcher {
+	key := strings.Join(extensions, "\x00")
+	if m, ok := compiledDomains.load(key); ok {
+		return m.(*domainMatcher)
+	}
+	m := &domainMatcher{extensions: make(map[string]bool, len(extensions))}
+	for _, extension := range extensions {
+		m.extensions[strings.ToLower(strings.TrimPrefix(extension, dot))] = true
+	}
+	return compiledDomains.store(key, m).(*domainMatcher)
+}
+
+// has tells if the label is one of the top level domains
+func (m *domainMatcher) has(label string) bool {
+	return m.extensions[strings.ToLower(label)]
+}
+
+func isDomainByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '-'
+}
@@ -0,0 +1,66 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPhraseMatcher(t *testing.T) {
+	m := compilePhrases([]string{"Forwarded", "Weitergeleitet", "Chuyển tiếp", "best regards"})
+	tests := map[string]bool{
+		"begin forwarded message":               true,
+		"anfang der weitergeleiteten nachricht": true,
+		"chuyển tiếp thư":                       true,
+		"unforwarded":                           false,
+		"nothing to see":                        false,
+	}
+	for text, expected := range tests {
+		if m.contains(text) != expected {
+			t.Errorf("%v: expected: `%v` but is `%v`", text, expected, !expected)
+		}
+	}
+
+	if length := m.prefixLength("best regards,"); length != len("best regards") {
+		t.Errorf("expected: `%v` but is `%v`", len("best regards"), length)
+	}
+	if !m.hasPrefixWord("forwarded by john") || m.hasPrefixWord("forwarded") {
+		t.Errorf("expected a prefix followed by a space")
+	}
+	if compilePhrases([]string{"Forwarded", "Weitergeleitet", "Chuyển tiếp", "best regards"}) != m {
+		t.Errorf("expected parsers with the same words to share the matcher")
+	}
+}
+
+func TestDomainMatcher(t *testing.T) {
+	m := compileDomains([]string{"com", ".nl"})
+	tests := map[string]bool{
+		"com":  true,
+		"NL":   true,
+		"comx": false,
+		"":     false,
+	}
+	for label, expected := range tests {
+		if m.has(label) != expected {
+			t.Errorf("%v: expected: `%v` but is `%v`", label, expected, !expected)
+		}
+	}
+}
+
+func TestCompiledMatchersAreBounded(t *testing.T) {
+	defaults := compilePhrases(DefaultOptions().Greetings)
+	for i := 0; i < maxCompiledMatchers*2; i++ {
+		NewParser(Options{Greetings: []string{fmt.Sprintf("cheers tenant %v", i)}})
+	}
+	if size := len(compiledPhrases.matchers); size > maxCompiledMatchers {
+		t.Errorf("expected at most `%v` matchers but is `%v`", maxCompiledMatchers, size)
+	}
+	if compilePhrases(DefaultOptions().Greetings) != defaults {
+		t.Errorf("expected the default greetings to stay shared")
+	}
+
+	// a parser with a word list which is not cached still works
+	parser := NewParser(Options{Greetings: []string{"cheers tenant 127"}})
+	if content := parser.Parse("Thanks\n\ncheers tenant 127\nJohn"); content != "Thanks" {
+		t.Errorf("expected: `%v` but is `%v`", "Thanks", content)
+	}
+}
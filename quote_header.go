@@ -0,0 +1,142 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxQuoteHeaderLines is the maximum amount of lines a wrapped quote header like
+// On DATE, NAME <EMAIL> wrote: can use
+const maxQuoteHeaderLines = 4
+
+// blockQuoteHeaderLength returns the amount of lines of a quote header which is longer than
+// the two lines detectQuotedEmailStart looks at like an Outlook header or a wrapped quote header,
+// it returns 0 when the line does not start such a header
+func (p *Parser) blockQuoteHeaderLength(lineIndex int, lines []*Line) int {
+	if length := p.outlookHeaderLength(lineIndex, lines); length > 0 {
+		return length
+	}
+	return p.wrappedQuoteHeaderLength(lineIndex, lines)
+}
+
+// wrappedQuoteHeaderLength returns the amount of lines of a quote header which is wrapped over
+// three or more lines or 0 e.g.
+// On Mon, Aug 26, 2019 at 4:37 PM A Really Long Display Name Of The Hiring Engine
+// <a-really-long-automated-email+1234556@humanresources.com>
+// wrote:
+func (p *Parser) wrappedQuoteHeaderLength(lineIndex int, lines []*Line) int {
+	line := lines[lineIndex]
+	if line.IsEmpty || line.IsQuoted || !p.on.hasPrefixWord(foldCase(line.ContentStripped)) {
+		return 0
+	}
+	joined := line.ContentStripped
+	for i := lineIndex + 1; i < len(lines) && i-lineIndex < maxQuoteHeaderLines; i++ {
+		next := lines[i]
+		if next.IsEmpty || next.IsQuoted {
+			return 0
+		}
+		joined += space + next.ContentStripped
+		if i-lineIndex >= 2 && strings.HasSuffix(next.ContentStripped, ":") &&
+			p.isQuotedEmailStart(foldCase(joined)) {
+			return i - lineIndex + 1
+		}
+	}
+	return 0
+}
+
+// splitMidLineQuoteHeaders splits the email in lines and puts quote headers which start in the middle
+// of a line on their own line e.g.
+// Thanks! On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+// lines which are longer than maxLineLength bytes are cut before they are searched when it is not zero,
+// the offsets stay the offsets in the email, it stops when done is closed
+func (p *Parser) splitMidLineQuoteHeaders(plainMail string, maxLineLength int, done <-chan struct{}) []sourceLine {
+	baseLines := strings.Split(plainMail, enter)
+	lengths := make([]int, len(baseLines))
+	for i, baseLine := range baseLines {
+		lengths[i] = len(baseLine)
+		if maxLineLength > 0 && len(baseLine) > maxLineLength {
+			baseLines[i] = truncateUTF8(baseLine, maxLineLength)
+		}
+	}
+
+	split := make([]sourceLine, 0, len(baseLines))
+	offset := 0
+	for i, baseLine := range baseLines {
+		if isDone(done) {
+			break
+		}
+		if start := p.midLineQuoteHeaderStart(baseLine, baseLines[i+1:]); start > 0 {
+			split = append(split,
+				sourceLine{content: strings.TrimRight(baseLine[:start], space), offset: offset, number: i + 1},
+				sourceLine{content: baseLine[start:], offset: offset + start, number: i + 1},
+			)
+		} else {
+			split = append(split, sourceLine{content: baseLine, offset: offset, number: i + 1})
+		}
+		offset += lengths[i] + len(enter)
+	}
+	return split
+}
+
+// maxMidLineQuoteHeaderLength is the maximum amount of bytes of a quote header which starts in the middle
+// of a line including the lines it is wrapped over, so only the end of a long line is searched for it
+const maxMidLineQuoteHeaderLength = 400
+
+// midLineQuoteHeaderStart returns the index where a quote header starts after the reply text
+// or -1, the header can continue on the next lines
+func (p *Parser) midLineQuoteHeaderStart(baseLine string, nextLines []string) int {
+	if _, depth := quotePrefix(baseLine, p.quotePrefixes); depth > 0 {
+		return -1
+	}
+	textStart := len(baseLine) - len(strings.TrimLeftFunc(baseLine, unicode.IsSpace))
+	from := len(baseLine) - maxMidLineQuoteHeaderLength
+	if from <= textStart {
+		from = textStart + 1
+	}
+	for start := from; start < len(baseLine); start++ {
+		if baseLine[start-1] != ' ' || !p.startsWithCapitalOn(baseLine[start:]) {
+			continue
+		}
+		if p.isQuoteHeaderFrom(baseLine[start:], nextLines) {
+			return start
+		}
+	}
+	return -1
+}
+
+// startsWithCapitalOn tells if the text starts with one of the on words followed by a space,
+// the header starts a sentence so on is written with a capital
+func (p *Parser) startsWithCapitalOn(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	if !unicode.IsUpper(r) {
+		return false
+	}
+	// the folded on word is at least as long as the written one
+	if end := p.on.longest() + len(space); end < len(text) {
+		text = text[:end]
+	}
+	return p.on.hasPrefixWord(foldCase(text))
+}
+
+// isQuoteHeaderFrom tells if the header and at most a few of the next lines form a quote header
+// which ends with a colon
+func (p *Parser) isQuoteHeaderFrom(header string, nextLines []string) bool {
+	joined := header
+	for i := 0; len(joined) <= maxMidLineQuoteHeaderLength; i++ {
+		if strings.HasSuffix(strings.TrimSpace(joined), ":") &&
+			p.isQuotedEmailStart(foldCase(removeMarkdown(removeWhitespace(joined)))) {
+			return true
+		}
+		if i >= len(nextLines) || i+1 >= maxQuoteHeaderLines {
+			return false
+		}
+		next := strings.TrimSpace(nextLines[i])
+		if _, depth := quotePrefix(nextLines[i], p.quotePrefixes); next == "" || depth > 0 ||
+			len(joined)+len(space)+len(next) > maxMidLineQuoteHeaderLength {
+			return false
+		}
+		joined += space + next
+	}
+	return false
+}
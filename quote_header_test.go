@@ -0,0 +1,101 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrappedQuoteHeader(t *testing.T) {
+	header := "On Mon, Aug 26, 2019 at 4:37 PM A Really Long Display Name\n" +
+		"Of The Hiring Engine\n" +
+		"<a-really-long-automated-email+1234556@humanresources.com>\n" +
+		"wrote:"
+	mails := map[string]string{
+		"bottom": "Sounds good\n\n" + header + "\n> dd\n> sldfj",
+		"top":    header + "\n> dd\n> sldfj\n\nSounds good",
+	}
+	for name, mail := range mails {
+		email := ParseEmail(mail)
+		if email.Reply() != "Sounds good" {
+			t.Errorf("%v: expected: `%v` but is `%v`", name, "Sounds good", email.Reply())
+		}
+		var found bool
+		for _, fragment := range email.Fragments {
+			if fragment.Kind != FragmentQuoteHeader {
+				continue
+			}
+			found = true
+			if fragment.Content != header {
+				t.Errorf("%v: expected: `%v` but is `%v`", name, header, fragment.Content)
+			}
+			address := fragment.Attribution.Address
+			if address == nil || address.Address != "a-really-long-automated-email+1234556@humanresources.com" {
+				t.Errorf("%v: expected the address of the hiring engine but is `%v`", name, address)
+			}
+		}
+		if !found {
+			t.Errorf("%v: expected a quote header", name)
+		}
+	}
+}
+
+func TestMidLineQuoteHeader(t *testing.T) {
+	tests := map[string]string{
+		"Thanks! On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\n> hello": "Thanks!",
+		"Sure thing. On Mon, Aug 26, 2019 at 4:37 PM The Hiring Engine <\n" +
+			"a-really-long-automated-email+1234556@humanresources.com> wrote:\n> dd": "Sure thing.",
+		"Merci ! Le lun. 4 nov. 2013 à 16:29, Jan de Smit <jan@example.org> a écrit :\n> bonjour": "Merci !",
+	}
+	for mail, expected := range tests {
+		email := ParseEmail(mail)
+		if email.Reply() != expected {
+			t.Errorf("expected: `%v` but is `%v`", expected, email.Reply())
+		}
+		if len(email.Fragments) < 2 || email.Fragments[1].Kind != FragmentQuoteHeader {
+			t.Errorf("expected a quote header after the reply in `%v`", mail)
+			continue
+		}
+		if email.Fragments[1].Attribution == nil || email.Fragments[1].Attribution.Name == "" {
+			t.Errorf("expected the name of the author in `%v`", email.Fragments[1].Content)
+		}
+	}
+}
+
+func TestMidLineQuoteHeaderNeedsHeader(t *testing.T) {
+	mails := []string{
+		"See you On Monday, I will bring the slides.",
+		"Thanks! On Monday we wrote: the plan",
+	}
+	for _, mail := range mails {
+		if Parse(mail) != mail {
+			t.Errorf("expected: `%v` but is `%v`", mail, Parse(mail))
+		}
+	}
+}
+
+func TestMidLineQuoteHeaderInLongLine(t *testing.T) {
+	p := defaultParser()
+	reply := strings.Repeat("The deploy went fine. ", 100)
+	lines := p.splitMidLineQuoteHeaders(reply+"On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:", 0, nil)
+	if len(lines) != 2 || !strings.HasPrefix(lines[1].content, "On Mon") {
+		t.Errorf("expected the header on its own line but is `%+v`", lines)
+	}
+
+	// every On is a candidate but only the end of the line can be a header
+	start := time.Now()
+	lines = p.splitMidLineQuoteHeaders(strings.Repeat("x On ", 100000)+":", 0, nil)
+	if len(lines) != 1 {
+		t.Errorf("expected: `%v` but is `%v`", 1, len(lines))
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected a linear search but took `%v`", elapsed)
+	}
+}
+
+func TestQuotingNeedsAddress(t *testing.T) {
+	mail := "Hi,\n\nQuoting the release notes:\nWe fixed the login bug.\n\nThanks for the help"
+	if reply := Parse(mail); reply != mail {
+		t.Errorf("expected: `%v` but is `%v`", mail, reply)
+	}
+}
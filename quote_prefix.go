@@ -0,0 +1,94 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+)
+
+// defaultQuotePrefixes are the characters which quote a line when no QuotePrefixes are given
+//
+//nolint:gochecknoglobals
+var defaultQuotePrefixes = []string{">"}
+
+// quotePrefix returns the quote characters at the start of a line with the whitespace between
+// and after them and the quote depth e.g. "> > " and 2 for > > text, whitespace before the first
+// character is allowed, characters other than > need whitespace after them so :) is not a quote
+func quotePrefix(line string, markers []string) (string, int) {
+	depth := 0
+	end := 0
+	i := len(line) - len(strings.TrimLeft(line, " \t"))
+	for i < len(line) {
+		marker := quoteMarkerAt(line[i:], markers)
+		if marker == "" {
+			break
+		}
+		depth++
+		i += len(marker)
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		end = i
+	}
+	return line[:end], depth
+}
+
+// quoteMarkerAt returns the quote character the text starts with or an empty string
+func quoteMarkerAt(text string, markers []string) string {
+	for _, marker := range markers {
+		if marker == "" || !strings.HasPrefix(text, marker) {
+			continue
+		}
+		rest := text[len(marker):]
+		if marker == ">" || rest == "" || rest[0] == ' ' || rest[0] == '\t' || quoteMarkerAt(rest, markers) != "" {
+			return marker
+		}
+	}
+	return ""
+}
+
+// unquoteLine removes one level of quoting e.g. > > text becomes > text,
+// a line which is quoted by indentation loses one tab
+func unquoteLine(line string, markers []string) string {
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	marker := quoteMarkerAt(line[start:], markers)
+	if marker == "" {
+		return strings.TrimPrefix(line, "\t")
+	}
+	return strings.TrimPrefix(line[start+len(marker):], space)
+}
+
+// markIndentedQuotes marks the lines which are indented with a tab directly below a quote header
+// as quoted and tells if it marked any, some clients quote this way instead of with >
+func (p *Parser) markIndentedQuotes(lines []*Line) bool {
+	var marked bool
+	for i := 0; i < len(lines) && !canceled(lines); i++ {
+		line := lines[i]
+		if line.IsEmpty || line.IsQuoted {
+			continue
+		}
+		if isQuoteStart, _ := p.detectQuotedEmailStart(i, line, lines); !isQuoteStart {
+			continue
+		}
+
+		start := i + p.quoteHeaderLength(i, lines)
+		for start < len(lines) && lines[start].IsEmpty {
+			start++
+		}
+		end := start
+		for end < len(lines) && (lines[end].IsEmpty || strings.HasPrefix(lines[end].Content, "\t")) {
+			end++
+		}
+		for j := start; j < end; j++ {
+			if lines[j].IsEmpty || lines[j].IsQuoted {
+				continue
+			}
+			lines[j].IsQuoted = true
+			lines[j].QuoteDepth = 1
+			lines[j].QuotePrefix = "\t"
+			marked = true
+		}
+		if end > start {
+			i = end - 1
+		}
+	}
+	return marked
+}
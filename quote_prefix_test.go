@@ -0,0 +1,89 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+)
+
+func TestQuotePrefix(t *testing.T) {
+	markers := []string{">", "|", ":"}
+	tests := map[string]struct {
+		prefix string
+		depth  int
+	}{
+		"> text":       {"> ", 1},
+		">text":        {">", 1},
+		"  > text":     {"  > ", 1},
+		"> >> text":    {"> >> ", 3},
+		"| text":       {"| ", 1},
+		"|text":        {"", 0},
+		": text":       {": ", 1},
+		":+1:":         {"", 0},
+		"| > text":     {"| > ", 2},
+		"text > other": {"", 0},
+	}
+	for line, expected := range tests {
+		prefix, depth := quotePrefix(line, markers)
+		if prefix != expected.prefix || depth != expected.depth {
+			t.Errorf("%v: expected: `%q %v` but is `%q %v`", line, expected.prefix, expected.depth, prefix, depth)
+		}
+	}
+}
+
+func TestAlternativeQuotePrefixes(t *testing.T) {
+	mail := `Thanks, the logs are clean.
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+| Did you check the logs?
+| | The deploy is done.`
+
+	if line := Analyze(mail).Lines[3]; line.IsQuoted {
+		t.Errorf("expected | not to quote by default but is `%v %q`", line.QuoteDepth, line.QuotePrefix)
+	}
+
+	p := NewParser(Options{QuotePrefixes: []string{">", "|"}})
+	email := p.ParseEmail(mail)
+	expected := "Thanks, the logs are clean."
+	if email.Reply() != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, email.Reply())
+	}
+	last := email.Fragments[len(email.Fragments)-1]
+	if last.Kind != FragmentQuotedReply {
+		t.Fatalf("expected: `%v` but is `%v`", FragmentQuotedReply, last.Kind)
+	}
+	nested := last.Lines[1]
+	if nested.QuoteDepth != 2 || nested.QuotePrefix != "| | " {
+		t.Errorf("expected: `2 \"| | \"` but is `%v %q`", nested.QuoteDepth, nested.QuotePrefix)
+	}
+}
+
+func TestIndentedQuote(t *testing.T) {
+	mail := "Thanks, the logs are clean.\n\n" +
+		"On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\n" +
+		"\tDid you check the logs?\n" +
+		"\n" +
+		"\tJohn\n"
+	document := Analyze(mail)
+	for _, i := range []int{3, 5} {
+		line := document.Lines[i]
+		if !line.IsQuoted || line.QuoteDepth != 1 || line.QuotePrefix != "\t" {
+			t.Errorf("%v: expected a quoted line but is `%v %v %q`", i, line.IsQuoted, line.QuoteDepth, line.QuotePrefix)
+		}
+	}
+
+	// indentation without quote header above it is part of the reply
+	mail = "Run these commands:\n\n\tgo build ./...\n\tgo test ./...\n\nThanks"
+	if reply := Parse(mail); reply != mail {
+		t.Errorf("expected: `%v` but is `%v`", mail, reply)
+	}
+}
+
+func TestThreadWithAlternativeQuotePrefixes(t *testing.T) {
+	mail := `Thanks!
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+: The deploy is done.`
+	messages := NewParser(Options{QuotePrefixes: []string{">", ":"}}).ParseThread(mail)
+	if len(messages) != 2 || messages[1].Body != "The deploy is done." {
+		t.Errorf("expected the quoted message `The deploy is done.` but is `%v`", messages)
+	}
+}
@@ -0,0 +1,54 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Span is the position of a fragment or region in the text which was parsed, so it can be
+// highlighted or collapsed in the original email, mail[Start:End] is the text without
+// the whitespace before and after it
+type Span struct {
+	// Start and End are byte offsets in the parsed text
+	Start int
+	End   int
+	// StartLine and EndLine are the line numbers in the parsed text starting at 1
+	StartLine int
+	EndLine   int
+}
+
+// sourceLine is a line with its position in the parsed text
+type sourceLine struct {
+	content string
+	offset  int
+	number  int
+}
+
+// spanOf returns the span of the lines without the empty lines and whitespace around them,
+// lines with only whitespace span all their characters
+func spanOf(lines []*Line) Span {
+	if len(lines) == 0 {
+		return Span{}
+	}
+	first := 0
+	for first < len(lines)-1 && lines[first].IsEmpty {
+		first++
+	}
+	last := len(lines) - 1
+	for last > first && lines[last].IsEmpty {
+		last--
+	}
+
+	start := lines[first].Offset
+	end := lines[last].Offset + len(lines[last].Content)
+	if !lines[first].IsEmpty {
+		start += len(lines[first].Content) - len(strings.TrimLeftFunc(lines[first].Content, unicode.IsSpace))
+		end -= len(lines[last].Content) - len(strings.TrimRightFunc(lines[last].Content, unicode.IsSpace))
+	}
+	return Span{
+		Start:     start,
+		End:       end,
+		StartLine: lines[first].Number,
+		EndLine:   lines[last].Number,
+	}
+}
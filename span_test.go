@@ -0,0 +1,72 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+)
+
+func TestFragmentSpans(t *testing.T) {
+	mails := map[string]string{
+		"lf": spanMail,
+		"crlf": "Thanks for the update.\r\n\r\nBest regards,\r\nKaren\r\n\r\n" +
+			"On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\r\n> The deploy is done.\r\n",
+	}
+	for name, mail := range mails {
+		for _, fragment := range ParseEmail(mail).Fragments {
+			if text := mail[fragment.Span.Start:fragment.Span.End]; text != fragment.Content {
+				t.Errorf("%v: expected: `%q` but is `%q`", name, fragment.Content, text)
+			}
+		}
+	}
+
+	expected := []Span{
+		{Start: 3, End: 25, StartLine: 2, EndLine: 2},
+		{Start: 27, End: 46, StartLine: 4, EndLine: 5},
+		{Start: 48, End: 114, StartLine: 7, EndLine: 7},
+		{Start: 115, End: 136, StartLine: 8, EndLine: 8},
+	}
+	email := ParseEmail(spanMail)
+	if len(email.Fragments) != len(expected) {
+		t.Fatalf("expected: `%v` fragments but is `%v`", len(expected), len(email.Fragments))
+	}
+	for i, fragment := range email.Fragments {
+		if fragment.Span != expected[i] {
+			t.Errorf("%v: expected: `%+v` but is `%+v`", fragment.Kind, expected[i], fragment.Span)
+		}
+	}
+}
+
+const spanMail = `
+  Thanks for the update.
+
+Best regards,
+Karen
+
+On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:
+> The deploy is done.
+`
+
+func TestMidLineQuoteHeaderSpan(t *testing.T) {
+	mail := "Thanks! On Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\n> The deploy is done."
+	email := ParseEmail(mail)
+	header := email.Fragments[1]
+	if header.Kind != FragmentQuoteHeader {
+		t.Fatalf("expected: `%v` but is `%v`", FragmentQuoteHeader, header.Kind)
+	}
+	if header.Span.Start != 8 || header.Span.StartLine != 1 || mail[header.Span.Start:header.Span.End] != header.Content {
+		t.Errorf("expected the header at 8 on line 1 but is `%+v`", header.Span)
+	}
+}
+
+func TestRegionSpans(t *testing.T) {
+	document := Analyze(spanMail)
+	for _, region := range document.Regions {
+		first := region.Lines[0]
+		if region.Span.StartLine < first.Number || region.Span.Start < first.Offset {
+			t.Errorf("%v: expected the span to start in the region but is `%+v`", region.Kind, region.Span)
+		}
+	}
+	last := document.Regions[len(document.Regions)-1]
+	if text := spanMail[last.Span.Start:last.Span.End]; text != "> The deploy is done." {
+		t.Errorf("expected: `%v` but is `%v`", "> The deploy is done.", text)
+	}
+}
@@ -0,0 +1,191 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReaderOptions changes how ParseReader reads an email, fields which are zero fall back to the defaults
+type ReaderOptions struct {
+	// Lookahead is the amount of lines read below the first quote header before it stops reading,
+	// an answer below the quoted text in these lines makes it read the whole email, defaults to 100
+	Lookahead int
+	// ReadAll reads the whole email and parses it like ParseEmail instead of stopping below the quote header
+	ReadAll bool
+	// MaxBytes is the maximum amount of bytes which are read, the rest of the email is left out
+	// and Email.Truncated is set, defaults to 32 MiB
+	MaxBytes int
+	// MaxLineLength is the maximum size of a line in bytes, longer lines are cut and Email.Truncated is set,
+	// defaults to 64 KiB
+	MaxLineLength int
+}
+
+const (
+	defaultStreamLookahead     = 100
+	defaultStreamMaxBytes      = 32 << 20
+	defaultStreamMaxLineLength = 64 << 10
+)
+
+// ParseReader reads the email line by line and splits it in fragments like ParseEmail, it stops reading
+// a fixed amount of lines below the first quote header when the reply is above it and the quoted text
+// has no answers below it, Email.Truncated is set then because the fragments end at the last line which was read
+func ParseReader(r io.Reader) (*Email, error) {
+	return defaultParser().ParseReader(r)
+}
+
+// ParseReader reads the email line by line and splits it in fragments like ParseEmail, it stops reading
+// a fixed amount of lines below the first quote header when the reply is above it and the quoted text
+// has no answers below it, Email.Truncated is set then because the fragments end at the last line which was read
+func (p *Parser) ParseReader(r io.Reader) (*Email, error) {
+	return p.ParseReaderOptions(r, ReaderOptions{})
+}
+
+// ParseReaderOptions reads the email like ParseReader with the given lookahead and limits
+func ParseReaderOptions(r io.Reader, options ReaderOptions) (*Email, error) {
+	return defaultParser().ParseReaderOptions(r, options)
+}
+
+// ParseReaderOptions reads the email like ParseReader with the given lookahead and limits
+func (p *Parser) ParseReaderOptions(r io.Reader, options ReaderOptions) (*Email, error) {
+	options = options.withDefaults()
+	reader := bufio.NewReader(r)
+	var text strings.Builder
+	// lineEnds are the offsets after the lines which were read so the first lines can be parsed again
+	var lineEnds []int
+	var truncated bool
+	// the email is parsed when checkAt lines are read, till the quote header is found the checks are further
+	// apart every time so the email is parsed a constant amount of times over
+	checkAt := options.Lookahead
+	for {
+		line, cut, err := readLine(reader, options.MaxLineLength)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if text.Len()+len(line) > options.MaxBytes {
+			truncated = true
+			break
+		}
+		text.WriteString(line)
+		truncated = truncated || cut
+		if err == io.EOF {
+			break
+		}
+		lineEnds = append(lineEnds, text.Len())
+		if options.ReadAll || len(lineEnds) < checkAt {
+			continue
+		}
+		email, next := p.replyAboveQuote(text.String(), lineEnds, options.Lookahead)
+		if email != nil {
+			email.Truncated = true
+			return email, nil
+		}
+		checkAt = next
+	}
+	email := p.ParseEmail(text.String())
+	email.Truncated = truncated
+	return email, nil
+}
+
+func (o ReaderOptions) withDefaults() ReaderOptions {
+	if o.Lookahead <= 0 {
+		o.Lookahead = defaultStreamLookahead
+	}
+	if o.MaxBytes <= 0 {
+		o.MaxBytes = defaultStreamMaxBytes
+	}
+	if o.MaxLineLength <= 0 {
+		o.MaxLineLength = defaultStreamMaxLineLength
+	}
+	return o
+}
+
+// readLine reads a line with its line break, a line which is longer than maxLength bytes is cut
+// and the rest of it is skipped so a line without line breaks is not kept in memory
+func readLine(reader *bufio.Reader, maxLength int) (string, bool, error) {
+	var line []byte
+	var length int
+	var ended bool
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		content := bytes.TrimSuffix(chunk, []byte(enter))
+		ended = len(content) < len(chunk)
+		length += len(content)
+		// a few bytes more are kept so the line can be cut without cutting a character in half
+		if room := maxLength + utf8.UTFMax - len(line); room < len(content) {
+			content = content[:room]
+		}
+		line = append(line, content...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		v := truncateUTF8(string(line), maxLength)
+		if ended {
+			v += enter
+		}
+		return v, length > maxLength, err
+	}
+}
+
+// replyAboveQuote returns the parsed email when the reply is above a quote header and the quoted text
+// has no answers in the lookahead lines below the header, otherwise it returns the amount of lines
+// after which it has to be checked again
+func (p *Parser) replyAboveQuote(plainMail string, lineEnds []int, lookahead int) (*Email, int) {
+	lines, c, _ := p.classify(plainMail)
+	header := topPostedQuoteHeader(lines, c)
+	if header == -1 {
+		return nil, 2 * len(lineEnds)
+	}
+	stopAt := lines[header].Number + lookahead
+	if stopAt > len(lineEnds) {
+		return nil, stopAt
+	}
+
+	// the lines below the lookahead are not used so the result does not depend on when it was checked
+	lines, c, forward := p.classify(plainMail[:lineEnds[stopAt-1]])
+	if header = topPostedQuoteHeader(lines, c); header == -1 || p.answersQuote(header, lines) {
+		// the reply is below or between the quoted text so the whole email is read
+		return nil, math.MaxInt
+	}
+	return p.emailFromClassification(lines, c, forward), 0
+}
+
+// topPostedQuoteHeader returns the index of the first quote header when the reply is above it or -1
+func topPostedQuoteHeader(lines []*Line, c *classification) int {
+	if c.isForward || c.layout != LayoutTopPosted {
+		return -1
+	}
+	for i := range lines {
+		if c.kind(i) == FragmentQuoteHeader {
+			return i
+		}
+	}
+	return -1
+}
+
+// answersQuote tells if a line which is not quoted and no quote header follows the quoted lines below the header
+// like in an interleaved reply
+func (p *Parser) answersQuote(header int, lines []*Line) bool {
+	var quotedSeen bool
+	for i := header; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case line.IsQuoted:
+			quotedSeen = true
+		case line.IsEmpty:
+		case p.isQuoteStart(i, lines):
+			i += p.quoteHeaderLength(i, lines) - 1
+		case quotedSeen:
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Parser) isQuoteStart(lineIndex int, lines []*Line) bool {
+	isQuoteStart, _ := p.detectQuotedEmailStart(lineIndex, lines[lineIndex], lines)
+	return isQuoteStart
+}
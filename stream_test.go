@@ -0,0 +1,143 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseReaderStopsAtQuote(t *testing.T) {
+	mail := longThread(10000)
+	reader := strings.NewReader(mail)
+	email, err := ParseReader(reader)
+	if err != nil {
+		t.Fatalf("expected: `%v` but is `%v`", nil, err)
+	}
+	if !email.Truncated {
+		t.Errorf("expected the email to be truncated")
+	}
+	if expected := Parse(mail); email.Reply() != expected {
+		t.Errorf("expected: `%v` but is `%v`", expected, email.Reply())
+	}
+	if read := len(mail) - reader.Len(); read > 16*1024 {
+		t.Errorf("expected the reader to stop after the quote header but read `%v` of `%v` bytes", read, len(mail))
+	}
+}
+
+func TestParseReaderReadsAnswers(t *testing.T) {
+	mails := map[string]string{
+		"interleaved": interleavedMail,
+		"answer below a long quote": "Hi John,\n\nOn Mon, Nov 4, 2013 at 4:29 PM, John Smith <john@smith.org> wrote:\n" +
+			strings.Repeat("> Did you check the logs?\n", 80) + "\n" +
+			strings.Repeat("Yes, I did and everything looks fine.\n", 40),
+		"forward": "FYI\n\n---------- Forwarded message ----------\nFrom: John Smith <john@smith.org>\n\n" +
+			strings.Repeat("> Did you check the logs?\n", 30),
+		"long reply": longReply(100),
+	}
+	for name, mail := range mails {
+		email, err := ParseReader(strings.NewReader(mail))
+		if err != nil {
+			t.Fatalf("%v: expected: `%v` but is `%v`", name, nil, err)
+		}
+		expected := ParseEmail(mail)
+		if email.Truncated || email.Reply() != expected.Reply() || len(email.Fragments) != len(expected.Fragments) {
+			t.Errorf("%v: expected: `%v` but is `%v`", name, expected.Reply(), email.Reply())
+		}
+	}
+}
+
+func TestParseReaderLookahead(t *testing.T) {
+	mail := "Hi John,\n\n" + longThread(100) + strings.Repeat("Let's deploy it tomorrow morning.\n", 100)
+
+	// the answers are further below the quote header than the lookahead
+	email, err := ParseReader(strings.NewReader(mail))
+	if err != nil || !email.Truncated {
+		t.Errorf("expected a truncated email but is `%v` `%v`", email, err)
+	}
+
+	expected := ParseEmail(mail)
+	for _, options := range []ReaderOptions{{ReadAll: true}, {Lookahead: 1000}} {
+		email, err = ParseReaderOptions(strings.NewReader(mail), options)
+		if err != nil {
+			t.Fatalf("%+v: expected: `%v` but is `%v`", options, nil, err)
+		}
+		if email.Truncated || email.Reply() != expected.Reply() {
+			t.Errorf("%+v: expected: `%v` but is `%v`", options, expected.Reply(), email.Reply())
+		}
+	}
+}
+
+func TestParseReaderLimits(t *testing.T) {
+	email, err := ParseReaderOptions(strings.NewReader(strings.Repeat("ä", 1<<20)+"\nThanks"), ReaderOptions{MaxLineLength: 1001})
+	if err != nil {
+		t.Fatalf("expected: `%v` but is `%v`", nil, err)
+	}
+	if expected := strings.Repeat("ä", 500) + "\nThanks"; !email.Truncated || email.Reply() != expected {
+		t.Errorf("expected the first line to be cut but is `%v` `%v`", email.Truncated, len(email.Reply()))
+	}
+
+	email, err = ParseReaderOptions(strings.NewReader(longReply(1000)), ReaderOptions{MaxBytes: 1000})
+	if err != nil {
+		t.Fatalf("expected: `%v` but is `%v`", nil, err)
+	}
+	if !email.Truncated || len(email.Reply()) > 1000 {
+		t.Errorf("expected at most `%v` bytes but is `%v` `%v`", 1000, email.Truncated, len(email.Reply()))
+	}
+}
+
+func TestParseReaderDataset(t *testing.T) {
+	mails, _ := filepath.Glob("./dataset/basemail/*")
+	signatures, _ := filepath.Glob("./dataset/signatures/*")
+	quotedReplies, _ := filepath.Glob("./dataset/quoted_reply/*")
+	read := func(path string) string {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+	for _, mailPath := range mails {
+		for _, signaturePath := range signatures {
+			for _, quotedReplyPath := range quotedReplies {
+				mail, signature, quotedReply := read(mailPath), read(signaturePath), read(quotedReplyPath)
+				for _, combined := range []string{
+					mail + "\n\n" + signature + "\n\n" + quotedReply,
+					quotedReply + "\n\n" + mail + "\n\n" + signature + "\n\n",
+				} {
+					email, err := ParseReader(strings.NewReader(combined))
+					if err != nil {
+						t.Fatalf("expected: `%v` but is `%v`", nil, err)
+					}
+					if expected := ParseEmail(combined); email.Reply() != expected.Reply() {
+						t.Errorf("%v, %v, %v: expected: `%v` but is `%v`",
+							mailPath, signaturePath, quotedReplyPath, expected.Reply(), email.Reply())
+					}
+				}
+			}
+		}
+	}
+}
+
+type failingReader struct {
+	content io.Reader
+	err     error
+}
+
+func (r *failingReader) Read(b []byte) (int, error) {
+	n, err := r.content.Read(b)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+func TestParseReaderError(t *testing.T) {
+	readErr := errors.New("connection reset")
+	_, err := ParseReader(&failingReader{content: strings.NewReader("Thanks!\n"), err: readErr})
+	if !errors.Is(err, readErr) {
+		t.Errorf("expected: `%v` but is `%v`", readErr, err)
+	}
+}
@@ -0,0 +1,65 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"strings"
+)
+
+// Message is one message of a conversation which is rebuilt from the quoted text of an email
+type Message struct {
+	// Attribution is nil for the newest message and for quoted text without a quote header
+	Attribution *Attribution
+	// Body is the text the author wrote without signature and quoted text
+	Body string
+	// Depth is 0 for the newest message and 1 more for every message quoted in it
+	Depth int
+}
+
+// ParseThread returns the newest message followed by every quoted or forwarded message in it,
+// so the conversation can be recovered from mail systems which only keep the latest message
+func ParseThread(plainMail string) []Message {
+	return defaultParser().ParseThread(plainMail)
+}
+
+// ParseThread returns the newest message followed by every quoted or forwarded message in it
+func (p *Parser) ParseThread(plainMail string) []Message {
+	return p.parseThread(plainMail, nil, 0)
+}
+
+// parseThread parses the email as one message and the quoted text in it as the messages before it
+func (p *Parser) parseThread(plainMail string, attribution *Attribution, depth int) []Message {
+	email := p.ParseEmail(plainMail)
+
+	var messages []Message
+	body := email.Reply()
+	// a quote level which only contains a deeper quote is not a message
+	if body != "" || attribution != nil || depth == 0 {
+		messages = append(messages, Message{Attribution: attribution, Body: body, Depth: depth})
+	}
+
+	var header *Attribution
+	var quoted []string
+	for _, fragment := range email.Fragments {
+		switch fragment.Kind {
+		case FragmentQuoteHeader:
+			if header == nil {
+				header = fragment.Attribution
+			}
+		case FragmentQuotedReply:
+			for _, line := range fragment.Lines {
+				quoted = append(quoted, unquoteLine(line.Content, p.quotePrefixes))
+			}
+		}
+	}
+	if quotedMail := strings.Join(quoted, enter); len(quoted) > 0 && quotedMail != plainMail {
+		messages = append(messages, p.parseThread(quotedMail, header, depth+1)...)
+	}
+
+	if email.Forward != nil {
+		var attribution *Attribution
+		if email.Forward.Header != nil {
+			attribution = attributionFromHeader(email.Forward.Header)
+		}
+		messages = append(messages, p.parseThread(email.Forward.Body, attribution, depth+1)...)
+	}
+	return messages
+}
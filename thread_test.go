@@ -0,0 +1,99 @@
+package email_reply_parser //nolint:stylecheck,golint
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseThread(t *testing.T) {
+	messages := ParseThread(threadMail)
+	expected := []struct {
+		name  string
+		body  string
+		depth int
+		date  time.Time
+	}{
+		{"", "Thanks, see you then!", 0, time.Time{}},
+		{"Bob Jones", "Sure, Tuesday works for me.", 1, time.Date(2021, time.March, 8, 10, 15, 0, 0, time.UTC)},
+		{"Alice Smith", "Can we meet next week?", 2, time.Date(2021, time.March, 7, 9, 0, 0, 0, time.UTC)},
+	}
+	if len(messages) != len(expected) {
+		t.Fatalf("expected %v messages but got %v: %v", len(expected), len(messages), messages)
+	}
+	for i, message := range messages {
+		if message.Body != expected[i].body {
+			t.Errorf("message %v: expected: `%v` but is `%v`", i, expected[i].body, message.Body)
+		}
+		if message.Depth != expected[i].depth {
+			t.Errorf("message %v: expected depth: `%v` but is `%v`", i, expected[i].depth, message.Depth)
+		}
+		if i == 0 {
+			if message.Attribution != nil {
+				t.Errorf("expected no attribution for the newest message but is `%v`", message.Attribution)
+			}
+			continue
+		}
+		if message.Attribution == nil {
+			t.Errorf("message %v: expected an attribution", i)
+			continue
+		}
+		if message.Attribution.Name != expected[i].name {
+			t.Errorf("message %v: expected: `%v` but is `%v`", i, expected[i].name, message.Attribution.Name)
+		}
+		if !message.Attribution.Date.Equal(expected[i].date) {
+			t.Errorf("message %v: expected: `%v` but is `%v`", i, expected[i].date, message.Attribution.Date)
+		}
+	}
+}
+
+const threadMail = `Thanks, see you then!
+
+On Mon, Mar 8, 2021 at 10:15 AM Bob Jones <bob@example.org> wrote:
+> Sure, Tuesday works for me.
+>
+> On Sun, Mar 7, 2021 at 9:00 AM Alice Smith <alice@example.org> wrote:
+> > Can we meet next week?
+> >
+`
+
+func TestParseThreadForward(t *testing.T) {
+	messages := ParseThread(forwardThreadMail)
+	if len(messages) != 2 {
+		t.Fatalf("expected 2 messages but got %v: %v", len(messages), messages)
+	}
+	if messages[0].Body != "FYI" {
+		t.Errorf("expected: `%v` but is `%v`", "FYI", messages[0].Body)
+	}
+	if messages[1].Attribution == nil || messages[1].Attribution.Name != "John Smith" {
+		t.Fatalf("expected the forwarded message to be written by John Smith but is `%v`", messages[1].Attribution)
+	}
+	if messages[1].Body != "The build is green again." {
+		t.Errorf("expected: `%v` but is `%v`", "The build is green again.", messages[1].Body)
+	}
+}
+
+const forwardThreadMail = `FYI
+
+---------- Forwarded message ---------
+From: John Smith <john.smith@example.org>
+Date: Thu, Oct 24, 2013 at 3:57 PM
+Subject: Build
+To: karen@webby.com
+
+The build is green again.
+`
+
+func TestUnquoteLine(t *testing.T) {
+	tests := map[string]string{
+		"> text":   "text",
+		">text":    "text",
+		"> > text": "> text",
+		">> text":  "> text",
+		"text":     "text",
+	}
+	for line, expected := range tests {
+		if unquoted := unquoteLine(line, defaultQuotePrefixes); unquoted != expected {
+			t.Errorf("expected: `%v` but is `%v`", expected, unquoted)
+		}
+	}
+}